@@ -0,0 +1,54 @@
+package gracefully
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var (
+	lastActivity atomic.Int64 // unix nanoseconds
+	inFlight     atomic.Int64
+)
+
+// Touch records activity at the current moment.
+// It postpones the shutdown triggered by WithIdleTimeout.
+//
+// It is safe for concurrent use and cheap enough to be called on every request.
+func Touch() {
+	lastActivity.Store(time.Now().UnixNano())
+}
+
+// BeginActivity marks the start of a unit of work (a request, a job, etc.)
+// and returns a func that marks its end. While at least one unit is in flight,
+// the process is never considered idle by WithIdleTimeout.
+//
+// Example:
+//
+//	done := gracefully.BeginActivity()
+//	defer done()
+func BeginActivity() (done func()) {
+	Touch()
+	inFlight.Add(1)
+
+	var once atomic.Bool
+	return func() {
+		if once.CompareAndSwap(false, true) {
+			Touch()
+			inFlight.Add(-1)
+		}
+	}
+}
+
+// idleFor reports for how long no activity has been recorded since the later of
+// the last Touch and since. It returns 0 while work is in flight.
+func idleFor(since time.Time) time.Duration {
+	if inFlight.Load() > 0 {
+		return 0
+	}
+
+	last := time.Unix(0, lastActivity.Load())
+	if last.Before(since) {
+		last = since
+	}
+	return time.Since(last)
+}
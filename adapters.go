@@ -0,0 +1,105 @@
+package gracefully
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// adapter adapts a close method to GracefulShutdownObject.
+type adapter struct {
+	name   string
+	target any // wrapped object; its pointer identifies the registration
+	close  func(context.Context) error
+}
+
+// FromCloser returns a GracefulShutdownObject that calls c.Close.
+//
+// Registrations are identified by c, not by the returned value, so
+// Unregister(FromCloser(c)) removes a previous Register(FromCloser(c)).
+// The same applies to FromStopper and FromShutdowner.
+//
+// Close runs in its own goroutine; if ctx is done first, GracefulShutdown
+// returns ctx.Err() and Close keeps running in the background.
+func FromCloser(c io.Closer) GracefulShutdownObject {
+	return &adapter{
+		name:   objectName(c),
+		target: c,
+		close:  func(context.Context) error { return c.Close() },
+	}
+}
+
+// FromStopper returns a GracefulShutdownObject that calls s.Stop.
+// See FromCloser for identity and context handling.
+func FromStopper(s Stopper) GracefulShutdownObject {
+	return &adapter{
+		name:   objectName(s),
+		target: s,
+		close: func(context.Context) error {
+			s.Stop()
+			return nil
+		},
+	}
+}
+
+// FromShutdowner returns a GracefulShutdownObject that calls s.Shutdown.
+// See FromCloser for identity and context handling; ctx is passed to
+// Shutdown as well, so s may also stop early on its own.
+func FromShutdowner(s Shutdowner) GracefulShutdownObject {
+	return &adapter{
+		name:   objectName(s),
+		target: s,
+		close:  s.Shutdown,
+	}
+}
+
+// FromFunc returns a GracefulShutdownObject that calls f and is reported under name
+// (see Registry.HookNames). Unlike RegisterFunc, it can be unregistered: keep
+// the returned value and pass it to Unregister.
+//
+// f runs in its own goroutine; if ctx is done first, GracefulShutdown returns ctx.Err().
+//
+// Example:
+//
+//	gracefully.MustRegister(gracefully.FromFunc("logger", func(context.Context) error {
+//		logger.Close() // no error to report
+//		return nil
+//	}))
+func FromFunc(name string, f func(context.Context) error) GracefulShutdownObject {
+	return &adapter{name: name, close: f}
+}
+
+// GracefulShutdown implements GracefulShutdownObject.
+func (a *adapter) GracefulShutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() { done <- a.close(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wrapped implements wrapper.
+func (a *adapter) wrapped() any { return a.target }
+
+// wrapper is implemented by adapters whose registration is identified
+// by the wrapped object rather than by the adapter itself.
+type wrapper interface {
+	wrapped() any
+}
+
+// objectKey returns the pointer that identifies v in a registry: the wrapped
+// object for adapters, v itself otherwise.
+func objectKey(v any) unsafe.Pointer {
+	if w, ok := v.(wrapper); ok && w.wrapped() != nil {
+		switch tv := reflect.ValueOf(w.wrapped()); tv.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Chan:
+			return tv.UnsafePointer()
+		}
+	}
+	return reflect.ValueOf(v).UnsafePointer()
+}
@@ -0,0 +1,192 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCloser struct {
+	err    error
+	block  chan struct{}
+	closed bool
+}
+
+func (c *testCloser) Close() error {
+	if c.block != nil {
+		<-c.block
+	}
+	c.closed = true
+	return c.err
+}
+
+type testStopper struct{ stopped bool }
+
+func (s *testStopper) Stop() { s.stopped = true }
+
+type testShutdowner struct{ ctx context.Context }
+
+func (s *testShutdowner) Shutdown(ctx context.Context) error {
+	s.ctx = ctx
+	return nil
+}
+
+func Test_FromCloser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/closes", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		c := &testCloser{}
+
+		// act
+		err := gracefully.FromCloser(c).GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, c.closed)
+	})
+
+	t.Run("err/returnsCloseError", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("boom")
+		c := &testCloser{err: boom}
+
+		// act
+		err := gracefully.FromCloser(c).GracefulShutdown(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("err/honorsContext", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		c := &testCloser{block: make(chan struct{})}
+		defer close(c.block)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := gracefully.FromCloser(c).GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("ok/unregisterByWrappedObject", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		c := &testCloser{}
+		assert.NoError(t, r.Register(gracefully.FromCloser(c)))
+
+		// act
+		dup := r.Register(gracefully.FromCloser(c))
+		ok := r.Unregister(gracefully.FromCloser(c))
+
+		// assert
+		assert.ErrorIs(t, dup, gracefully.ErrAlreadyRegistered)
+		assert.True(t, ok)
+		assert.Empty(t, r.HookNames())
+	})
+
+	t.Run("ok/namedAfterWrappedType", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+
+		// act
+		r.MustRegister(gracefully.FromCloser(&testCloser{}))
+
+		// assert
+		assert.Equal(t, []string{"*gracefully_test.testCloser"}, r.HookNames())
+	})
+}
+
+func Test_FromStopper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/stops", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		s := &testStopper{}
+
+		// act
+		err := gracefully.FromStopper(s).GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, s.stopped)
+	})
+}
+
+func Test_FromShutdowner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/passesContext", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		s := &testShutdowner{}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// act
+		err := gracefully.FromShutdowner(s).GracefulShutdown(ctx)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, ctx, s.ctx)
+	})
+}
+
+func Test_FromFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/namedAndUnregistrable", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		called := false
+		obj := gracefully.FromFunc("logger", func(context.Context) error {
+			called = true
+			return nil
+		})
+		r.MustRegister(obj)
+		names := r.HookNames()
+
+		// act
+		ok := r.Unregister(obj)
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.Equal(t, []string{"logger"}, names)
+		assert.True(t, ok)
+		assert.True(t, me.IsEmpty())
+		assert.False(t, called)
+	})
+
+	t.Run("err/honorsContext", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		release := make(chan struct{})
+		defer close(release)
+		obj := gracefully.FromFunc("stuck", func(context.Context) error {
+			<-release
+			return nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// act
+		err := obj.GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
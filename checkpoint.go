@@ -0,0 +1,145 @@
+package gracefully
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"time"
+)
+
+// Checkpoint file format:
+//
+//	magic     [4]byte "GRCK"
+//	version   uint16  checkpointVersion
+//	nameLen   uint16
+//	name      [nameLen]byte
+//	createdAt int64   unix nanoseconds
+//	dataLen   uint64
+//	data      [dataLen]byte
+//	checksum  uint32  CRC-32 (IEEE) of all the preceding bytes
+//
+// All integers are big-endian.
+const (
+	checkpointMagic   = "GRCK"
+	checkpointVersion = 1
+)
+
+// CheckpointStore persists checkpoints by name. Implementations must make Save
+// atomic: a failed or interrupted Save must leave the previous checkpoint intact.
+type CheckpointStore interface {
+	// Save stores data under name, replacing the previous checkpoint.
+	Save(ctx context.Context, name string, data []byte) error
+	// Load returns the data stored under name, or an error wrapping
+	// ErrNoCheckpoint if there is none.
+	Load(ctx context.Context, name string) ([]byte, error)
+}
+
+// checkpoint adapts a Checkpointer to a lifecycle component, see Checkpoint.
+type checkpoint struct {
+	name  string
+	c     Checkpointer
+	store CheckpointStore
+}
+
+// Checkpoint returns a lifecycle component that restores c from store on Start
+// (see Registry.Start) and saves a snapshot of c to store on shutdown.
+// A missing checkpoint is not an error on Start; a corrupted one is reported
+// with ErrCheckpointCorrupted, and c is not restored.
+//
+// Checkpoints are saved with a format version, the name, the creation time and a checksum.
+// Registrations are identified by c, so Unregister(Checkpoint(name, c, store)) works.
+//
+// Example:
+//
+//	store := gracefully.FileStore("/var/lib/app")
+//	gracefully.MustRegister(gracefully.Checkpoint("counter", counter, store))
+//	if errs := gracefully.Start(ctx); !errs.IsEmpty() {
+//		log.Fatal(errs)
+//	}
+func Checkpoint(name string, c Checkpointer, store CheckpointStore) GracefulShutdownObject {
+	return &checkpoint{name: name, c: c, store: store}
+}
+
+// wrapped implements wrapper.
+func (cp *checkpoint) wrapped() any { return cp.c }
+
+// Start implements Starter.
+func (cp *checkpoint) Start(ctx context.Context) error {
+	raw, err := cp.store.Load(ctx, cp.name)
+	if errors.Is(err, ErrNoCheckpoint) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("checkpoint %s: %w", cp.name, err)
+	}
+
+	data, err := decodeCheckpoint(cp.name, raw)
+	if err != nil {
+		return fmt.Errorf("checkpoint %s: %w", cp.name, err)
+	}
+	if err := cp.c.Restore(data); err != nil {
+		return fmt.Errorf("checkpoint %s: restore: %w", cp.name, err)
+	}
+	return nil
+}
+
+// GracefulShutdown implements GracefulShutdownObject.
+func (cp *checkpoint) GracefulShutdown(ctx context.Context) error {
+	data, err := cp.c.Snapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("checkpoint %s: snapshot: %w", cp.name, err)
+	}
+	if err := cp.store.Save(ctx, cp.name, encodeCheckpoint(cp.name, time.Now(), data)); err != nil {
+		return fmt.Errorf("checkpoint %s: %w", cp.name, err)
+	}
+	return nil
+}
+
+func encodeCheckpoint(name string, createdAt time.Time, data []byte) []byte {
+	buf := make([]byte, 0, 4+2+2+len(name)+8+8+len(data)+4)
+	buf = append(buf, checkpointMagic...)
+	buf = binary.BigEndian.AppendUint16(buf, checkpointVersion)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(name)))
+	buf = append(buf, name...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(createdAt.UnixNano()))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(data)))
+	buf = append(buf, data...)
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+}
+
+// decodeCheckpoint validates raw and returns the data.
+func decodeCheckpoint(name string, raw []byte) ([]byte, error) {
+	const fixed = 4 + 2 + 2 + 8 + 8 + 4
+	if len(raw) < fixed || !bytes.HasPrefix(raw, []byte(checkpointMagic)) {
+		return nil, fmt.Errorf("%w: bad header", ErrCheckpointCorrupted)
+	}
+
+	body, sum := raw[:len(raw)-4], binary.BigEndian.Uint32(raw[len(raw)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCheckpointCorrupted)
+	}
+
+	if v := binary.BigEndian.Uint16(body[4:]); v != checkpointVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrCheckpointCorrupted, v)
+	}
+
+	nameLen := int(binary.BigEndian.Uint16(body[6:]))
+	rest := body[8:]
+	if len(rest) < nameLen+16 {
+		return nil, fmt.Errorf("%w: truncated", ErrCheckpointCorrupted)
+	}
+	if got := string(rest[:nameLen]); got != name {
+		return nil, fmt.Errorf("%w: saved for %q", ErrCheckpointCorrupted, got)
+	}
+	rest = rest[nameLen+8:] // skip createdAt
+
+	dataLen := binary.BigEndian.Uint64(rest)
+	data := rest[8:]
+	if uint64(len(data)) != dataLen {
+		return nil, fmt.Errorf("%w: truncated", ErrCheckpointCorrupted)
+	}
+	return data, nil
+}
@@ -0,0 +1,83 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileStore is a CheckpointStore that keeps each checkpoint in its own file.
+type fileStore struct {
+	dir string
+}
+
+// FileStore returns a CheckpointStore that keeps each checkpoint in dir/<name>.ckpt.
+// Save writes a temporary file, syncs it and renames it over the previous checkpoint,
+// so a crash during Save leaves the previous checkpoint intact. dir is created if needed.
+func FileStore(dir string) CheckpointStore {
+	return &fileStore{dir: dir}
+}
+
+// Save implements CheckpointStore.
+func (s *fileStore) Save(_ context.Context, name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// persist the rename; best effort, directories can't be synced on every platform
+	if d, err := os.Open(s.dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// Load implements CheckpointStore.
+func (s *fileStore) Load(_ context.Context, name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoCheckpoint, path)
+	}
+	return data, err
+}
+
+func (s *fileStore) path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid checkpoint name %q", name)
+	}
+	return filepath.Join(s.dir, name+".ckpt"), nil
+}
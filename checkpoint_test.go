@@ -0,0 +1,181 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+// counter is a Checkpointer with an int state.
+type counter struct {
+	n           int
+	snapshotErr error
+	restored    bool
+}
+
+func (c *counter) Snapshot(context.Context) ([]byte, error) {
+	if c.snapshotErr != nil {
+		return nil, c.snapshotErr
+	}
+	return []byte(strconv.Itoa(c.n)), nil
+}
+
+func (c *counter) Restore(data []byte) error {
+	n, err := strconv.Atoi(string(data))
+	c.n, c.restored = n, true
+	return err
+}
+
+// saveCounter runs a registry with a checkpointed counter set to n through shutdown.
+func saveCounter(t *testing.T, store gracefully.CheckpointStore, n int) {
+	r := gracefully.NewRegistry()
+	r.MustRegister(gracefully.Checkpoint("counter", &counter{n: n}, store))
+	me := r.Shutdown(context.Background())
+	assert.True(t, me.IsEmpty(), me.Error())
+}
+
+func Test_Checkpoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/snapshotsAndRestores", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		dir := t.TempDir()
+		store := gracefully.FileStore(dir)
+		saveCounter(t, store, 42)
+		c := &counter{}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Checkpoint("counter", c, store))
+
+		// act
+		errs := r.Start(context.Background())
+
+		// assert
+		assert.True(t, errs.IsEmpty())
+		assert.Equal(t, 42, c.n)
+		entries, _ := os.ReadDir(dir)
+		assert.Len(t, entries, 1, "no temporary files are left")
+		assert.Equal(t, []string{"checkpoint counter"}, r.HookNames())
+	})
+
+	t.Run("ok/missingCheckpoint", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		c := &counter{}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Checkpoint("counter", c, gracefully.FileStore(t.TempDir())))
+
+		// act
+		errs := r.Start(context.Background())
+
+		// assert
+		assert.True(t, errs.IsEmpty())
+		assert.False(t, c.restored)
+	})
+
+	t.Run("err/corrupted", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		dir := t.TempDir()
+		store := gracefully.FileStore(dir)
+		saveCounter(t, store, 42)
+		path := filepath.Join(dir, "counter.ckpt")
+		raw, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		raw[len(raw)-6] ^= 0xff // flip a data byte
+		assert.NoError(t, os.WriteFile(path, raw, 0o644))
+		c := &counter{}
+
+		// act
+		err = gracefully.Checkpoint("counter", c, store).(gracefully.Starter).Start(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrCheckpointCorrupted)
+		assert.ErrorContains(t, err, "checksum mismatch")
+		assert.False(t, c.restored)
+	})
+
+	t.Run("err/truncated", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		dir := t.TempDir()
+		store := gracefully.FileStore(dir)
+		assert.NoError(t, store.Save(context.Background(), "counter", []byte("GRCK")))
+
+		// act
+		err := gracefully.Checkpoint("counter", &counter{}, store).(gracefully.Starter).Start(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrCheckpointCorrupted)
+	})
+
+	t.Run("err/savedForAnotherName", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		dir := t.TempDir()
+		store := gracefully.FileStore(dir)
+		saveCounter(t, store, 42)
+		assert.NoError(t, os.Rename(filepath.Join(dir, "counter.ckpt"), filepath.Join(dir, "other.ckpt")))
+
+		// act
+		err := gracefully.Checkpoint("other", &counter{}, store).(gracefully.Starter).Start(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrCheckpointCorrupted)
+		assert.ErrorContains(t, err, `saved for "counter"`)
+	})
+
+	t.Run("err/snapshotFails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("boom")
+		store := gracefully.FileStore(t.TempDir())
+		cp := gracefully.Checkpoint("counter", &counter{snapshotErr: boom}, store)
+
+		// act
+		err := cp.GracefulShutdown(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, boom)
+		_, loadErr := store.Load(context.Background(), "counter")
+		assert.ErrorIs(t, loadErr, gracefully.ErrNoCheckpoint)
+	})
+}
+
+func Test_FileStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/replacesPrevious", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		store := gracefully.FileStore(filepath.Join(t.TempDir(), "nested"))
+		assert.NoError(t, store.Save(context.Background(), "a", []byte("1")))
+
+		// act
+		err := store.Save(context.Background(), "a", []byte("2"))
+
+		// assert
+		assert.NoError(t, err)
+		data, err := store.Load(context.Background(), "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("2"), data)
+	})
+
+	t.Run("err/invalidName", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		store := gracefully.FileStore(t.TempDir())
+
+		// act
+		err := store.Save(context.Background(), "../escape", nil)
+
+		// assert
+		assert.ErrorContains(t, err, "invalid checkpoint name")
+	})
+}
@@ -70,3 +70,40 @@ type Registerer interface {
 type GracefulShutdownObject interface {
 	GracefulShutdown(context.Context) error
 }
+
+// Reloader is an interface for objects that can re-read their configuration
+// (or reopen files, reconnect, etc.) without restarting the process.
+// Reload is invoked by Registry.Reload, e.g. when SIGHUP is routed to ActionReload.
+type Reloader interface {
+	Reload(context.Context) error
+}
+
+// Starter is an interface for components that need to be started before use
+// (e.g. servers, consumers, connection pools). Objects implementing both Starter
+// and GracefulShutdownObject are managed as full lifecycle components: Registry.Start
+// starts them in registration order, and shuts down the already started ones
+// in reverse order if one of them fails to start.
+type Starter interface {
+	Start(context.Context) error
+}
+
+// Checkpointer is implemented by components with in-memory state that should
+// survive a restart. Use Checkpoint to snapshot it on shutdown and restore it on start.
+type Checkpointer interface {
+	// Snapshot returns the serialized state.
+	Snapshot(context.Context) ([]byte, error)
+	// Restore replaces the state with a snapshot returned by Snapshot.
+	Restore([]byte) error
+}
+
+// Stopper is implemented by objects that stop with Stop(), e.g. *time.Ticker.
+// Use FromStopper to register them.
+type Stopper interface {
+	Stop()
+}
+
+// Shutdowner is implemented by objects that stop with Shutdown(ctx) error,
+// e.g. *http.Server. Use FromShutdowner to register them.
+type Shutdowner interface {
+	Shutdown(context.Context) error
+}
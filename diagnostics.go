@@ -0,0 +1,71 @@
+package gracefully
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/pprof"
+	"time"
+)
+
+// unsafeFileChars matches the characters replaced in hook names used as file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// watchHung starts the timer of WithTimeoutDiagnostics for a hook started with ctx.
+// The returned function stops it and returns the captured stacks and the file
+// they were written to, if any. A hook started past the deadline isn't watched:
+// the stacks captured for the hook that overran it already show the hang.
+func watchHung(ctx context.Context, name string, c *registryConfig) (stop func() (stacks []byte, file string)) {
+	deadline, ok := ctx.Deadline()
+	if c.diagFraction <= 0 || !ok || time.Until(deadline) <= 0 {
+		return func() ([]byte, string) { return nil, "" }
+	}
+
+	var (
+		stacks []byte
+		file   string
+		done   = make(chan struct{})
+	)
+	after := time.Duration(float64(time.Until(deadline)) * c.diagFraction)
+	t := time.AfterFunc(after, func() {
+		defer close(done)
+		stacks, file = captureStacks(name, c.diagDir)
+	})
+
+	return func() ([]byte, string) {
+		if t.Stop() {
+			return nil, ""
+		}
+		<-done
+		return stacks, file
+	}
+}
+
+// captureStacks returns the stacks of all goroutines and writes them to a file in
+// dir, or to the log if dir isn't set (or the file can't be written), so they
+// aren't lost if the hook never returns.
+func captureStacks(name, dir string) ([]byte, string) {
+	var buf bytes.Buffer
+	_ = pprof.Lookup("goroutine").WriteTo(&buf, 2)
+
+	if dir != "" {
+		file := filepath.Join(dir, fmt.Sprintf("gracefully-%s-%s.txt",
+			time.Now().Format("20060102T150405.000"), unsafeFileChars.ReplaceAllString(name, "_")))
+		err := os.MkdirAll(dir, 0o755)
+		if err == nil {
+			err = os.WriteFile(file, buf.Bytes(), 0o644)
+		}
+		if err == nil {
+			log.Printf("gogracefully: Hook %s is still running - captured goroutine stacks to %s\n", name, file)
+			return buf.Bytes(), file
+		}
+		log.Printf("gogracefully: Can't write goroutine stacks - %v\n", err)
+	}
+
+	log.Printf("gogracefully: Hook %s is still running - goroutine stacks:\n%s", name, buf.Bytes())
+	return buf.Bytes(), ""
+}
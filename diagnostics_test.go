@@ -0,0 +1,182 @@
+package gracefully_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+// stuckInFlush is a hook that ignores ctx until released.
+func stuckInFlush(release <-chan struct{}) func(context.Context) error {
+	return func(context.Context) error {
+		<-release
+		return context.DeadlineExceeded
+	}
+}
+
+func Test_WithTimeoutDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/attachesStacksToErrorAndReport", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		dir := t.TempDir()
+		release := make(chan struct{})
+		r := gracefully.NewRegistry(gracefully.WithTimeoutDiagnostics(0.5), gracefully.WithDiagnosticsDir(dir))
+		r.MustRegister(gracefully.FromFunc("flusher", stuckInFlush(release)))
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		time.AfterFunc(200*time.Millisecond, func() { close(release) })
+
+		// act
+		me := r.Shutdown(ctx)
+
+		// assert
+		var hung *gracefully.HungHookError
+		assert.ErrorAs(t, me.MaybeUnwrap(), &hung)
+		assert.Equal(t, "flusher", hung.Name)
+		assert.Contains(t, string(hung.Stacks), "goroutine ")
+		assert.ErrorIs(t, hung, context.DeadlineExceeded)
+
+		report, _ := r.Report()
+		assert.Equal(t, hung.Stacks, report.Hooks[0].Stacks)
+		assert.Equal(t, hung.File, report.Hooks[0].StacksFile)
+		written, err := os.ReadFile(hung.File)
+		assert.NoError(t, err)
+		assert.Equal(t, hung.Stacks, written)
+	})
+
+	t.Run("ok/fastHookUntouched", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry(gracefully.WithTimeoutDiagnostics(1))
+		r.MustRegister(gracefully.FromFunc("fast", func(context.Context) error { return nil }))
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// act
+		me := r.Shutdown(ctx)
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		report, _ := r.Report()
+		assert.Nil(t, report.Hooks[0].Stacks)
+	})
+
+	t.Run("edge/noDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		release := make(chan struct{})
+		r := gracefully.NewRegistry()
+		r.Configure(gracefully.WithTimeoutDiagnostics(0.1))
+		r.MustRegister(gracefully.FromFunc("slow", stuckInFlush(release)))
+		time.AfterFunc(50*time.Millisecond, func() { close(release) })
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		var hung *gracefully.HungHookError
+		assert.False(t, errors.As(me.MaybeUnwrap(), &hung))
+	})
+
+	t.Run("edge/hooksStartedPastDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		dir := t.TempDir()
+		release := make(chan struct{})
+		r := gracefully.NewRegistry(gracefully.WithTimeoutDiagnostics(1), gracefully.WithDiagnosticsDir(dir))
+		r.MustRegister(&hungHook{release: release})
+		r.MustRegister(&hungHook{release: release})
+		time.AfterFunc(20*time.Millisecond, func() { close(release) })
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		// act
+		_ = r.Shutdown(ctx)
+
+		// assert
+		report, _ := r.Report()
+		for _, h := range report.Hooks {
+			assert.Empty(t, h.StacksFile, h.Name)
+			assert.Zero(t, len(h.Stacks), h.Name)
+		}
+		files, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("ok/childInheritsOptions", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		release := make(chan struct{})
+		r := gracefully.NewRegistry(gracefully.WithTimeoutDiagnostics(0.5))
+		child, err := r.NewChild("consumers")
+		assert.NoError(t, err)
+		child.MustRegister(gracefully.FromFunc("consumer", stuckInFlush(release)))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		time.AfterFunc(100*time.Millisecond, func() { close(release) })
+
+		// act
+		me := r.Shutdown(ctx)
+
+		// assert
+		var hung *gracefully.HungHookError
+		assert.ErrorAs(t, me.MaybeUnwrap(), &hung)
+		assert.Equal(t, "consumer", hung.Name)
+	})
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, to capture the log.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func Test_WithTimeoutDiagnostics_logsStacksWithoutDir(t *testing.T) {
+	// swaps the log output; avoid parallel here
+	var out syncBuffer
+	prev := log.Writer()
+	log.SetOutput(&out)
+	t.Cleanup(func() { log.SetOutput(prev) })
+
+	// arrange
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	r := gracefully.NewRegistry(gracefully.WithTimeoutDiagnostics(0.5))
+	r.MustRegister(&hungHook{release: release})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// act: the hook never returns while the test runs
+	go r.Shutdown(ctx)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "goroutine stacks:") &&
+			strings.Contains(out.String(), "hungHook).GracefulShutdown")
+	}, time.Second, time.Millisecond)
+}
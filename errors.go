@@ -1,6 +1,11 @@
 package gracefully
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lif0/pkg/utils/errx"
+)
 
 // ErrShutdownCalled is returned when Register is invoked after Shutdown.
 // Use errors.Is(err, ErrShutdownCalled) to detect this case.
@@ -9,3 +14,151 @@ var ErrShutdownCalled = errors.New("registration disabled after Shutdown")
 // ErrAlreadyRegistered is returned when trying to register the same instance twice
 // (or another instance with the same identity). Use errors.Is(err, ErrAlreadyRegistered).
 var ErrAlreadyRegistered = errors.New("instance already registered")
+
+// ErrStartCalled is returned when Start is invoked more than once.
+var ErrStartCalled = errors.New("start already called")
+
+// ErrNotShutdown is returned by Reset when the registry has not been shut down.
+var ErrNotShutdown = errors.New("registry has not been shut down")
+
+// ErrNoPendingShutdown is returned by AbortShutdown when no shutdown has been triggered.
+var ErrNoPendingShutdown = errors.New("no pending shutdown to abort")
+
+// ErrShutdownInProgress is returned by AbortShutdown when the shutdown hooks have
+// already started and the shutdown can no longer be aborted.
+var ErrShutdownInProgress = errors.New("shutdown hooks already started")
+
+// ErrSignalReceived is the shutdown cause reported when an OS signal started the shutdown.
+// The signal name is included in the wrapping error.
+var ErrSignalReceived = errors.New("received system signal")
+
+// ErrUserTrigger is the shutdown cause reported when a channel passed to
+// WithUserChanSignal started the shutdown.
+var ErrUserTrigger = errors.New("received user trigger")
+
+// ErrParentDied is the shutdown cause reported by WithParentDeathSignal
+// when the parent process exits.
+var ErrParentDied = errors.New("parent process died")
+
+// ErrInputClosed is the shutdown cause reported by WithReaderEOF and WithStdinClosed
+// when the watched input reaches EOF (or fails).
+var ErrInputClosed = errors.New("input closed")
+
+// ErrFileTriggered is the shutdown cause reported by WithFileTrigger
+// when the trigger file appears or is modified. The file path is included in the wrapping error.
+var ErrFileTriggered = errors.New("trigger file appeared")
+
+// ErrMaxLifetime is the shutdown cause reported by WithMaxLifetime
+// when the process has reached its lifetime.
+var ErrMaxLifetime = errors.New("max lifetime reached")
+
+// ErrIdleTimeout is the shutdown cause reported by WithIdleTimeout
+// when no activity has been recorded for the configured duration.
+var ErrIdleTimeout = errors.New("idle timeout reached")
+
+// ErrMemoryLimit is the shutdown cause reported by WithMemoryLimitTrigger.
+// The cause is a *MemoryLimitError, use errors.As to get the measured value.
+var ErrMemoryLimit = errors.New("memory limit reached")
+
+// MemoryLimitError is the shutdown cause reported by WithMemoryLimitTrigger.
+// It matches ErrMemoryLimit with errors.Is.
+type MemoryLimitError struct {
+	// Used is the measured memory usage in bytes.
+	Used uint64
+	// Threshold is the configured threshold in bytes.
+	Threshold uint64
+	// Source is where Used was taken from: "cgroup" or "runtime".
+	Source string
+}
+
+// Error implements the error interface.
+func (e *MemoryLimitError) Error() string {
+	return fmt.Sprintf("%s: %d bytes used (%s), threshold %d bytes", ErrMemoryLimit, e.Used, e.Source, e.Threshold)
+}
+
+// Is reports whether target is ErrMemoryLimit.
+func (e *MemoryLimitError) Is(target error) bool {
+	return target == ErrMemoryLimit
+}
+
+// ErrServeFailed is the shutdown cause reported by Serve and ListenAndServe
+// when the server fails with an error other than http.ErrServerClosed.
+var ErrServeFailed = errors.New("server failed")
+
+// ErrRestarted is the shutdown cause reported when a new copy of the process
+// took over the listeners (see WithRestart).
+var ErrRestarted = errors.New("restarted")
+
+// ErrWorkAbandoned is returned by Tracker.GracefulShutdown when the shutdown
+// context is done before all active work has finished.
+var ErrWorkAbandoned = errors.New("active work abandoned")
+
+// ErrGoroutinesRunning is returned by Group.GracefulShutdown when the shutdown
+// context is done before all goroutines have returned. The names of the goroutines
+// are included in the wrapping error.
+var ErrGoroutinesRunning = errors.New("goroutines did not exit")
+
+// ErrProcessKilled is returned by Process.GracefulShutdown when the process did not
+// exit within the grace period (or before the shutdown context was done) and was killed.
+var ErrProcessKilled = errors.New("process killed")
+
+// ErrConnectionsInUse is returned by the SQLDB adapter and TrackingListener when the
+// shutdown context is done while connections are still in use. The pool (or the
+// remaining connections) is closed anyway.
+var ErrConnectionsInUse = errors.New("connections still in use")
+
+// ErrNoCheckpoint is returned by CheckpointStore.Load when nothing was saved under the name.
+var ErrNoCheckpoint = errors.New("no checkpoint")
+
+// ErrCheckpointCorrupted is returned when a saved checkpoint fails validation:
+// bad magic, unsupported format version, name mismatch, truncation or checksum mismatch.
+var ErrCheckpointCorrupted = errors.New("checkpoint corrupted")
+
+// ErrSpoolCorrupted is returned by Spool.Replay when the spool file ends with a
+// damaged or incomplete record (e.g. the process crashed while spilling).
+// The records before it are replayed.
+var ErrSpoolCorrupted = errors.New("spool corrupted")
+
+// ErrHookAbandoned is reported for a hook that was still running when its context
+// was done and was left behind, see WithHardDeadline.
+var ErrHookAbandoned = errors.New("hook abandoned")
+
+// HungHookError is reported in place of the error of a hook that failed after
+// running past the threshold of WithTimeoutDiagnostics. It carries the stacks of
+// all goroutines captured while the hook was still running.
+type HungHookError struct {
+	Name   string
+	Stacks []byte // pprof goroutine profile (debug=2)
+	File   string // file the stacks were written to, see WithDiagnosticsDir
+	Err    error
+}
+
+func (e *HungHookError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("hook %s hung (goroutine stacks in %s): %v", e.Name, e.File, e.Err)
+	}
+	return fmt.Sprintf("hook %s hung (%d bytes of goroutine stacks captured): %v", e.Name, len(e.Stacks), e.Err)
+}
+
+// Unwrap returns the error of the hook.
+func (e *HungHookError) Unwrap() error {
+	return e.Err
+}
+
+// ChildError is reported by a parent registry when a child registry (see NewChild)
+// fails to shut down. It nests the child's errors under the child's name.
+// errors.Is and errors.As look into the nested errors.
+type ChildError struct {
+	Name string
+	Errs errx.MultiError
+}
+
+// Error implements the error interface.
+func (e *ChildError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Errs.Error())
+}
+
+// Unwrap returns the nested errors.
+func (e *ChildError) Unwrap() []error {
+	return e.Errs
+}
@@ -8,3 +8,6 @@ require (
 	github.com/lif0/pkg/concurrency v1.2.0 // indirect
 	github.com/lif0/pkg/utils v1.2.0 // indirect
 )
+
+// uses the middleware subpackage, which is not released yet
+replace github.com/lif0/go-gracefully => ../..
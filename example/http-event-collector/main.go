@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/lif0/go-gracefully"
+	"github.com/lif0/go-gracefully/middleware"
 )
 
 func main() {
@@ -16,6 +17,13 @@ func main() {
 		gracefully.WithSysSignal(),
 	)
 
+	// rejects new requests once draining starts and waits for in-flight ones on shutdown;
+	// registered first, so requests are drained before the batchers flush
+	drain, err := middleware.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// init service
 	serverEventCollector := NewBatcher("server_events.log")
 	userEventCollector := NewBatcher("user_events.log")
@@ -28,7 +36,7 @@ func main() {
 	go serverEventCollector.Run()
 	go userEventCollector.Run()
 
-	go runServer(serverEventCollector, userEventCollector)
+	go runServer(drain, serverEventCollector, userEventCollector)
 
 	gracefully.WaitShutdown()
 	if !gracefully.GlobalError().IsEmpty() {
@@ -37,13 +45,8 @@ func main() {
 	log.Println("app is done...")
 }
 
-func runServer(serverEventCollector, userEventCollector *eventBatcher) {
+func runServer(drain *middleware.Middleware, serverEventCollector, userEventCollector *eventBatcher) {
 	http.HandleFunc("/user/event", func(w http.ResponseWriter, r *http.Request) {
-		if gracefully.GetStatus() != gracefully.StatusRunning {
-			w.Write([]byte("service is shutting down, try be later."))
-			return
-		}
-
 		events, err := toStringArr(r)
 		if err != nil {
 			w.Write([]byte(err.Error()))
@@ -55,11 +58,6 @@ func runServer(serverEventCollector, userEventCollector *eventBatcher) {
 	})
 
 	http.HandleFunc("/server/event", func(w http.ResponseWriter, r *http.Request) {
-		if gracefully.GetStatus() != gracefully.StatusRunning {
-			w.Write([]byte("service is shutting down, try be later."))
-			return
-		}
-
 		events, err := toStringArr(r)
 		if err != nil {
 			w.Write([]byte(err.Error()))
@@ -70,7 +68,7 @@ func runServer(serverEventCollector, userEventCollector *eventBatcher) {
 		w.Write([]byte("OK"))
 	})
 
-	if err := http.ListenAndServe(":8080", http.DefaultServeMux); err != nil {
+	if err := http.ListenAndServe(":8080", drain.Wrap(http.DefaultServeMux)); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -10,6 +10,7 @@ import (
 var (
 	defaultRegistry = NewRegistry()
 	globalErrors    = concurrency.NewSyncValue(errx.MultiError{})
+	reloadErrors    = concurrency.NewSyncValue(errx.MultiError{})
 
 	DefaultRegisterer Registerer = defaultRegistry
 )
@@ -24,6 +25,18 @@ func GlobalError() errx.MultiError {
 	return errs
 }
 
+// GlobalReloadError returns the errors collected from reloads started by the
+// shutdown trigger (see ActionReload). They are kept apart from GlobalError.
+func GlobalReloadError() errx.MultiError {
+	var errs errx.MultiError
+
+	reloadErrors.ReadValue(func(v *errx.MultiError) {
+		errs = append([]error(nil), *v...) // make copy
+	})
+
+	return errs
+}
+
 // SetGlobal sets a custom GracefullyRegister as the global registry.
 // This allows replacing the default registry with a user-provided one
 // (e.g. for testing purposes).
@@ -32,6 +45,12 @@ func SetGlobal(gr *Registry) {
 	DefaultRegisterer = defaultRegistry
 }
 
+// GlobalRegistry returns the global registry used by the package-level functions
+// and the shutdown trigger.
+func GlobalRegistry() *Registry {
+	return defaultRegistry
+}
+
 // Register registers the provided GracefulShutdownObject with the DefaultRegisterer.
 //
 // Register is a shortcut for DefaultRegisterer.Register(c).
@@ -62,6 +81,29 @@ func MustRegister(igss ...GracefulShutdownObject) {
 	DefaultRegisterer.MustRegister(igss...)
 }
 
+// Start starts the lifecycle components registered in the global registry.
+//
+// Start is a shortcut for the global Registry.Start(ctx).
+func Start(ctx context.Context) errx.MultiError {
+	return defaultRegistry.Start(ctx)
+}
+
+// Reset reopens the global registry after shutdown (see Registry.Reset) and re-arms
+// the shutdown trigger: the status goes back to StatusRunning and ShutdownCause is cleared.
+// Trigger options that watch a condition (e.g. WithMaxLifetime, WithFileTrigger)
+// start watching it anew. It is meant for in-process restarts and tests.
+//
+// A Tracker, middleware.Middleware or TrackingListener stays closed once it has been shut down,
+// even if it is kept with WithKeepRegistrations: create new ones after Reset.
+func Reset(opts ...ResetOption) error {
+	if err := defaultRegistry.Reset(opts...); err != nil {
+		return err
+	}
+
+	resetTrigger()
+	return nil
+}
+
 // WaitShutdown blocks the calling goroutine until with the DefaultRegisterer
 // has finished shutdown all registered instances
 //
@@ -69,3 +111,31 @@ func MustRegister(igss ...GracefulShutdownObject) {
 func WaitShutdown() {
 	DefaultRegisterer.WaitShutdown()
 }
+
+// Configure applies opts to the global registry.
+//
+// Configure is a shortcut for GlobalRegistry().Configure(opts...).
+func Configure(opts ...RegistryOption) {
+	defaultRegistry.Configure(opts...)
+}
+
+// Report returns the report of the last completed shutdown of the global registry.
+//
+// Report is a shortcut for GlobalRegistry().Report().
+func Report() (ShutdownReport, bool) {
+	return defaultRegistry.Report()
+}
+
+// RegisterReloader registers the provided Reloader with the global registry.
+//
+// RegisterReloader is a shortcut for the global Registry.RegisterReloader(rl).
+func RegisterReloader(rl Reloader) error {
+	return defaultRegistry.RegisterReloader(rl)
+}
+
+// UnregisterReloader removes the provided Reloader from the global registry.
+//
+// UnregisterReloader is a shortcut for the global Registry.UnregisterReloader(rl).
+func UnregisterReloader(rl Reloader) bool {
+	return defaultRegistry.UnregisterReloader(rl)
+}
@@ -33,6 +33,7 @@ func TestGlobal(t *testing.T) {
 	gracefully.SetGlobal(r)
 
 	assert.Equal(t, gracefully.StatusRunning, gracefully.GetStatus())
+	assert.NoError(t, gracefully.ShutdownCause())
 
 	// assert
 	assert.Equal(t, r, gracefully.DefaultRegisterer)
@@ -92,6 +93,7 @@ func TestGlobal(t *testing.T) {
 
 	time.Sleep(time.Millisecond * 150)
 	assert.Equal(t, gracefully.StatusStopped, gracefully.GetStatus())
+	assert.ErrorIs(t, gracefully.ShutdownCause(), gracefully.ErrUserTrigger)
 
 	// pkg have bug in empty err it have len(multi_err) == 1
 	globalErr := gracefully.GlobalError()
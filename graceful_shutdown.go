@@ -2,33 +2,105 @@ package gracefully
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/lif0/pkg/concurrency/chanx"
 	"github.com/lif0/pkg/utils/errx"
 )
 
 var (
-	status   atomic.Uint32
-	statusCh atomic.Pointer[chan struct{}]
+	status        atomic.Uint32
+	shutdownCause atomic.Pointer[error]
+
+	// causes passed to TriggerShutdown
+	manual = struct {
+		mu sync.Mutex
+		ch chan error // read by the trigger set last; buffers a request made before it
+	}{ch: make(chan error, 1)}
+
+	// status subscribers and history, see WatchStatus and StatusHistory
+	watchersMu sync.Mutex
+	watchers   = map[chan Status]struct{}{}
+	history    []StatusChange
+)
+
+// maxStatusHistory bounds StatusHistory; the oldest changes are dropped first.
+const maxStatusHistory = 32
+
+// StatusChange is a recorded status transition, see StatusHistory.
+type StatusChange struct {
+	Status Status
+	At     time.Time
+}
+
+// drainState is the state of the shutdown started by the trigger.
+type drainState byte
+
+const (
+	drainIdle    drainState = iota // nothing triggered (or the shutdown was aborted)
+	drainPending                   // Draining, hooks haven't started, AbortShutdown is possible
+	drainRunning                   // hooks have started, the shutdown can't be aborted
 )
 
-func init() { setStatus(StatusRunning) }
+var drain struct {
+	mu    sync.Mutex
+	state drainState
+	abort chan struct{} // closed by AbortShutdown while drainPending
+	hooks chan struct{} // closed when the state becomes drainRunning, see hooksStarted
+	rearm chan struct{} // closed and replaced by AbortShutdown and resetTrigger, see runWatcher
+	gen   uint64        // incremented by resetTrigger, so a finishing shutdown doesn't override it
+}
+
+func init() {
+	drain.rearm = make(chan struct{})
+	setStatus(StatusRunning)
+}
 
 // GetStatus returns the current service status during graceful shutdown.
 //
 // It is safe for concurrent use and reflects the latest recorded state.
 func GetStatus() Status { return Status(status.Load()) }
 
+// ShutdownCause returns the reason the shutdown trigger started graceful shutdown,
+// or nil if it has not been triggered yet.
+//
+// Use errors.Is to check the cause, e.g. errors.Is(cause, ErrParentDied).
+func ShutdownCause() error {
+	if cause := shutdownCause.Load(); cause != nil {
+		return *cause
+	}
+	return nil
+}
+
+// StatusHistory returns the recorded status transitions, oldest first.
+// The first entry is the StatusRunning set at program start.
+func StatusHistory() []StatusChange {
+	watchersMu.Lock()
+	defer watchersMu.Unlock()
+
+	return append([]StatusChange(nil), history...)
+}
+
 func setStatus(nS Status) {
 	status.Store(uint32(nS))
 
-	if statusCh.Load() != nil {
+	watchersMu.Lock()
+	defer watchersMu.Unlock()
+
+	if len(history) == maxStatusHistory {
+		history = append(history[:0], history[1:]...)
+	}
+	history = append(history, StatusChange{Status: nS, At: time.Now()})
+
+	for ch := range watchers {
 		select {
-		case *statusCh.Load() <- struct{}{}:
+		case ch <- nS:
 		default:
 		}
 	}
@@ -38,26 +110,28 @@ func setStatus(nS Status) {
 //
 // When the status changes, all provided callback functions are invoked.
 // Each callback receives the new status value as an argument.
+// The subscription ends when ctx is done.
 func WatchStatus(ctx context.Context, callbacks ...func(newStatus Status)) {
-	if statusCh.Load() == nil {
-		ch := make(chan struct{}, 3)
-		statusCh.CompareAndSwap(nil, &ch)
-	}
+	ch := make(chan Status, 8)
 
-	go func() {
-		lastStatus := status.Load()
-		e := *statusCh.Load()
+	watchersMu.Lock()
+	watchers[ch] = struct{}{}
+	lastStatus := GetStatus()
+	watchersMu.Unlock()
 
+	go func() {
 		for {
 			select {
 			case <-ctx.Done():
-				close(*statusCh.Load())
-				statusCh.Store(nil)
+				watchersMu.Lock()
+				delete(watchers, ch)
+				watchersMu.Unlock()
 				return
-			case <-e:
-				if status.Load() != lastStatus {
-					lastStatus = status.Load()
-					newStatus := GetStatus()
+			case newStatus := <-ch:
+				// use the delivered value rather than re-reading the current status,
+				// so quick successive transitions are not collapsed into the last one.
+				if newStatus != lastStatus {
+					lastStatus = newStatus
 					for i := range callbacks {
 						callbacks[i](newStatus)
 					}
@@ -67,6 +141,109 @@ func WatchStatus(ctx context.Context, callbacks ...func(newStatus Status)) {
 	}()
 }
 
+// AbortShutdown cancels a shutdown started by the trigger that is still waiting
+// to run hooks (see WithPreStopDelay). The status goes back to StatusRunning,
+// status watchers are notified, ShutdownCause is reset and the trigger is re-armed.
+// Trigger options that watch a condition (e.g. WithMaxLifetime, WithIdleTimeout,
+// WithMemoryLimitTrigger) start watching it anew, as if the trigger had just been set.
+//
+// It returns ErrNoPendingShutdown if nothing was triggered, and
+// ErrShutdownInProgress once the hooks have started.
+func AbortShutdown() error {
+	drain.mu.Lock()
+	defer drain.mu.Unlock()
+
+	switch drain.state {
+	case drainIdle:
+		return ErrNoPendingShutdown
+	case drainRunning:
+		return ErrShutdownInProgress
+	}
+
+	close(drain.abort)
+	drain.abort = nil
+	drain.state = drainIdle
+	close(drain.rearm)
+	drain.rearm = make(chan struct{})
+
+	shutdownCause.Store(nil)
+	setStatus(StatusRunning)
+
+	log.Printf("gogracefully: Shutdown aborted\n")
+	return nil
+}
+
+// TriggerShutdown asks the shutdown trigger (see SetShutdownTrigger) to start
+// graceful shutdown with the given cause, as if a signal had been received.
+// It doesn't block; if a request is already pending, cause is dropped.
+// Unlike a second signal, a request made while a shutdown is in progress is
+// ignored and doesn't force exit.
+// A request made before SetShutdownTrigger is handled once the trigger is set.
+// If SetShutdownTrigger was called more than once, only the trigger set last receives it.
+func TriggerShutdown(cause error) {
+	manual.mu.Lock()
+	defer manual.mu.Unlock()
+
+	select {
+	case manual.ch <- cause:
+	default:
+	}
+}
+
+// takeManual gives TriggerShutdown a new channel for the trigger being set, with
+// the request still pending, if any. Earlier triggers don't receive requests anymore.
+func takeManual() <-chan error {
+	manual.mu.Lock()
+	defer manual.mu.Unlock()
+
+	ch := make(chan error, 1)
+	select {
+	case cause := <-manual.ch:
+		ch <- cause
+	default:
+	}
+	manual.ch = ch
+	return ch
+}
+
+// hooksStarted returns a channel that is closed once the shutdown started by the
+// trigger runs the hooks of the global registry, i.e. after the pre-stop delay.
+func hooksStarted() <-chan struct{} {
+	drain.mu.Lock()
+	defer drain.mu.Unlock()
+
+	if drain.hooks == nil {
+		drain.hooks = make(chan struct{})
+	}
+	return drain.hooks
+}
+
+// resetTrigger re-arms the shutdown trigger after the global registry was reset.
+func resetTrigger() {
+	drain.mu.Lock()
+	defer drain.mu.Unlock()
+
+	if drain.abort != nil {
+		close(drain.abort)
+	}
+	drain.state = drainIdle
+	drain.abort = nil
+	drain.hooks = nil
+	drain.gen++
+	close(drain.rearm)
+	drain.rearm = make(chan struct{})
+
+	manual.mu.Lock()
+	select {
+	case <-manual.ch: // a request made for the previous run
+	default:
+	}
+	manual.mu.Unlock()
+
+	shutdownCause.Store(nil)
+	setStatus(StatusRunning)
+}
+
 // SetShutdownTrigger sets up a trigger for Registry.Shutdown.
 //
 // This global function takes a context for cancellation; if the context is canceled,
@@ -78,51 +255,208 @@ func SetShutdownTrigger(ctx context.Context, opts ...TriggerOption) {
 		opt(c)
 	}
 
+	manualch := takeManual()
+
 	go func() {
-		var once sync.Once // ensures graceful Shutdown is attempted only once
-		var firstSignal bool = false
 		singleUserChan := chanx.FanIn(ctx, c.usrch...)
 
+		var routech chan os.Signal // stays nil (never ready) when no routes are set
+		if len(c.routes) > 0 {
+			routech = make(chan os.Signal, 1)
+			for sig := range c.routes {
+				signal.Notify(routech, sig)
+			}
+			defer signal.Stop(routech)
+		}
+
+		causech := make(chan watcherCause)
+		for _, w := range c.watchers {
+			go runWatcher(ctx, w, causech)
+		}
+
 		for {
+			var cause error
+			var rearm chan<- (<-chan struct{})
+			forceOnRepeat := true // received during a shutdown, it forces exit
+
 			select {
 			case <-ctx.Done():
 				return
 			case sig := <-c.sysch:
+				if action, ok := c.routes[sig]; ok && action != ActionShutdown {
+					continue // routed away from shutdown, handled via routech
+				}
 				log.Printf("gogracefully: Received system signal - %s\n", sig.String())
+				cause = fmt.Errorf("%w: %s", ErrSignalReceived, sig)
 			case <-singleUserChan:
 				log.Printf("gogracefully: Received user trigger\n")
+				cause = ErrUserTrigger
+			case sig := <-routech:
+				action := c.routes[sig]
+				log.Printf("gogracefully: Received system signal - %s (%s)\n", sig.String(), action)
+				if action != ActionShutdown {
+					runAction(ctx, c, action)
+					continue
+				}
+				cause = fmt.Errorf("%w: %s", ErrSignalReceived, sig)
+			case wc := <-causech:
+				cause, rearm = wc.cause, wc.rearm
+				log.Printf("gogracefully: Received trigger - %v\n", cause)
+				forceOnRepeat = false
+			case cause = <-manualch:
+				log.Printf("gogracefully: Received trigger - %v\n", cause)
+				forceOnRepeat = false
 			}
 
-			setStatus(StatusDraining)
+			drain.mu.Lock()
+			if drain.state != drainIdle {
+				if rearm != nil {
+					rearm <- drain.rearm
+				}
+				drain.mu.Unlock()
+
+				if !forceOnRepeat {
+					// watchers and TriggerShutdown only start a shutdown; they must
+					// not kill the hooks of the one already in progress
+					log.Printf("gogracefully: Shutdown already in progress - ignoring trigger\n")
+					continue
+				}
 
-			firstSignal = !firstSignal
-
-			if firstSignal {
-				go func() { // because we should be have can handle second signal.
-					once.Do(func() {
-						defer setStatus(StatusStopped)
-
-						shutdownCtx := ctx
-						if c.timeout > 0 {
-							sctx, cancel := context.WithTimeout(ctx, c.timeout)
-							shutdownCtx = sctx
-							defer cancel()
-						}
-
-						// log.Printf("gogracefully: Starting graceful shutdown with timeout\n")
-						if muErr := defaultRegistry.Shutdown(shutdownCtx); muErr != nil && !muErr.IsEmpty() {
-							globalErrors.MutateValue(func(v *errx.MultiError) {
-								v.Append(muErr)
-							})
-						}
-						log.Printf("gogracefully: Graceful shutdown completed. Use gogracefully.GlobalErrors for checks errors\n")
-					})
-				}()
-			} else {
 				// Second or subsequent signal: Force exit
 				log.Printf("gogracefully: Received additional signal - forcing exit\n")
 				os.Exit(1) // Or os.Exit(130) for SIGINT, etc.
 			}
+
+			abort := make(chan struct{})
+			drain.state = drainPending
+			drain.abort = abort
+			if rearm != nil {
+				rearm <- drain.rearm
+			}
+
+			shutdownCause.Store(&cause)
+			setStatus(StatusDraining)
+			drain.mu.Unlock()
+
+			go shutdown(ctx, c, abort) // because we should be have can handle second signal.
 		}
 	}()
 }
+
+// watcherCause is a cause fired by a watcher. The trigger loop replies on rearm
+// with a channel that is closed once the trigger is re-armed (see drain.rearm).
+type watcherCause struct {
+	cause error
+	rearm chan<- (<-chan struct{})
+}
+
+// runWatcher runs w until ctx is done. Each time the trigger is re-armed after w
+// fired (by AbortShutdown or Reset), w is canceled and started again, so its
+// condition is watched anew.
+func runWatcher(ctx context.Context, w watcher, causech chan<- watcherCause) {
+	for {
+		wctx, cancel := context.WithCancel(ctx)
+
+		var rearmed <-chan struct{}
+		w(wctx, func(cause error) {
+			rearm := make(chan (<-chan struct{}), 1)
+			select {
+			case causech <- watcherCause{cause: cause, rearm: rearm}:
+			case <-wctx.Done():
+				return
+			}
+
+			rearmed = <-rearm
+			go func(rearmed <-chan struct{}) {
+				select {
+				case <-rearmed:
+					cancel() // lets a watcher waiting for the shutdown return
+				case <-wctx.Done():
+				}
+			}(rearmed)
+		})
+
+		if rearmed == nil { // ctx is done
+			cancel()
+			return
+		}
+
+		select {
+		case <-rearmed:
+		case <-ctx.Done():
+		}
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// shutdown waits for the pre-stop delay and runs the hooks of the global registry,
+// unless the shutdown is aborted or ctx is done first.
+func shutdown(ctx context.Context, c *triggerConfig, abort <-chan struct{}) {
+	if c.preStopDelay > 0 {
+		t := time.NewTimer(c.preStopDelay)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+		case <-abort:
+			return
+		case <-ctx.Done():
+			// the trigger was canceled: the pending shutdown is dropped, not run
+			drain.mu.Lock()
+			defer drain.mu.Unlock()
+
+			if drain.abort == abort {
+				drain.state = drainIdle
+				drain.abort = nil
+			}
+			return
+		}
+	}
+
+	drain.mu.Lock()
+	select {
+	case <-abort:
+		drain.mu.Unlock()
+		return
+	default:
+	}
+	drain.state = drainRunning
+	drain.abort = nil
+	if drain.hooks == nil {
+		drain.hooks = make(chan struct{})
+	}
+	close(drain.hooks)
+	gen := drain.gen
+	drain.mu.Unlock()
+
+	defer func() {
+		drain.mu.Lock()
+		defer drain.mu.Unlock()
+
+		if drain.gen == gen {
+			setStatus(StatusStopped)
+		}
+	}()
+
+	shutdownCtx := ctx
+	if c.timeout > 0 {
+		sctx, cancel := context.WithTimeout(ctx, c.timeout)
+		shutdownCtx = sctx
+		defer cancel()
+	}
+
+	// log.Printf("gogracefully: Starting graceful shutdown with timeout\n")
+	if muErr := defaultRegistry.Shutdown(shutdownCtx); muErr != nil && !muErr.IsEmpty() {
+		globalErrors.MutateValue(func(v *errx.MultiError) {
+			v.Append(muErr)
+		})
+	}
+	if report, ok := defaultRegistry.Report(); ok && report.Spilled() > 0 {
+		log.Printf("gogracefully: %d record(s) spilled during shutdown\n", report.Spilled())
+	}
+	log.Printf("gogracefully: Graceful shutdown completed. Use gogracefully.GlobalErrors for checks errors\n")
+}
@@ -0,0 +1,426 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// resetTriggerState restores the global trigger state before and after the test.
+func resetTriggerState(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		drain.mu.Lock()
+		drain.state = drainIdle
+		drain.abort = nil
+		drain.hooks = nil
+		drain.mu.Unlock()
+
+		manual.mu.Lock()
+		manual.ch = make(chan error, 1)
+		manual.mu.Unlock()
+
+		shutdownCause.Store(nil)
+		setStatus(StatusRunning)
+	}
+
+	reset()
+	t.Cleanup(reset)
+
+	oldRegistry, oldRegisterer := defaultRegistry, DefaultRegisterer
+	t.Cleanup(func() { defaultRegistry, DefaultRegisterer = oldRegistry, oldRegisterer })
+}
+
+func waitStatus(t *testing.T, want Status) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool { return GetStatus() == want }, time.Second, time.Millisecond)
+}
+
+func Test_AbortShutdown(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	var calls atomic.Int32
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+
+	var mu sync.Mutex
+	var seen []Status
+	WatchStatus(t.Context(), func(newStatus Status) {
+		mu.Lock()
+		seen = append(seen, newStatus)
+		mu.Unlock()
+	})
+
+	userCh := make(chan struct{}, 1)
+	SetShutdownTrigger(t.Context(),
+		WithCustomSystemSignal(nil),
+		WithUserChanSignal(userCh),
+		WithPreStopDelay(100*time.Millisecond),
+	)
+
+	// act + assert: nothing to abort yet
+	assert.ErrorIs(t, AbortShutdown(), ErrNoPendingShutdown)
+
+	// act + assert: abort during pre-stop delay
+	userCh <- struct{}{}
+	waitStatus(t, StatusDraining)
+	assert.ErrorIs(t, ShutdownCause(), ErrUserTrigger)
+
+	assert.NoError(t, AbortShutdown())
+	assert.Equal(t, StatusRunning, GetStatus())
+	assert.NoError(t, ShutdownCause())
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(0), calls.Load(), "hooks must not run after abort")
+	assert.ErrorIs(t, AbortShutdown(), ErrNoPendingShutdown)
+
+	// act + assert: trigger is re-armed, hooks can't be aborted once started
+	userCh <- struct{}{}
+	waitStatus(t, StatusDraining)
+	r.WaitShutdown()
+	waitStatus(t, StatusStopped)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.ErrorIs(t, AbortShutdown(), ErrShutdownInProgress)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return assert.ObjectsAreEqual([]Status{StatusDraining, StatusRunning, StatusDraining, StatusStopped}, seen)
+	}, time.Second, time.Millisecond)
+}
+
+func Test_AbortShutdown_restartsWatchers(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	var calls atomic.Int32
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+
+	path := filepath.Join(t.TempDir(), "stop")
+	SetShutdownTrigger(t.Context(),
+		WithCustomSystemSignal(nil),
+		WithMaxLifetime(50*time.Millisecond, 0),
+		WithFileTrigger(path, time.Millisecond),
+		WithPreStopDelay(time.Minute),
+	)
+
+	// act + assert: lifetime fires, abort, lifetime fires again
+	waitStatus(t, StatusDraining)
+	assert.ErrorIs(t, ShutdownCause(), ErrMaxLifetime)
+	assert.NoError(t, AbortShutdown())
+
+	waitStatus(t, StatusDraining)
+	assert.ErrorIs(t, ShutdownCause(), ErrMaxLifetime)
+	assert.NoError(t, AbortShutdown())
+
+	// act + assert: file fires, abort, file fires again once changed
+	assert.NoError(t, os.WriteFile(path, nil, 0o644))
+	waitStatus(t, StatusDraining)
+	assert.ErrorIs(t, ShutdownCause(), ErrFileTriggered)
+	assert.NoError(t, AbortShutdown())
+	assert.FileExists(t, path)
+
+	time.Sleep(20 * time.Millisecond) // the restarted watcher takes the kept file as is
+	assert.NoError(t, os.WriteFile(path, []byte("stop"), 0o644))
+	waitStatus(t, StatusDraining)
+	assert.ErrorIs(t, ShutdownCause(), ErrFileTriggered)
+	assert.NoError(t, AbortShutdown())
+
+	assert.Equal(t, int32(0), calls.Load(), "hooks must not run after abort")
+}
+
+func Test_SetShutdownTrigger_canceledDuringPreStopDelay(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	var calls atomic.Int32
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+	ctx, cancel := context.WithCancel(t.Context())
+	SetShutdownTrigger(ctx, WithCustomSystemSignal(nil), WithPreStopDelay(time.Minute))
+	TriggerShutdown(ErrUserTrigger)
+	waitStatus(t, StatusDraining)
+
+	// act
+	cancel()
+
+	// assert
+	assert.Eventually(t, func() bool {
+		drain.mu.Lock()
+		defer drain.mu.Unlock()
+		return drain.state == drainIdle
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(0), calls.Load(), "hooks must not run after the trigger is canceled")
+	assert.NotEqual(t, StatusStopped, GetStatus())
+}
+
+func Test_hooksStarted(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	started := hooksStarted()
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil), WithPreStopDelay(50*time.Millisecond))
+
+	// act + assert: not during the pre-stop delay
+	TriggerShutdown(ErrUserTrigger)
+	waitStatus(t, StatusDraining)
+	select {
+	case <-started:
+		t.Fatalf("closed during the pre-stop delay")
+	default:
+	}
+
+	// act + assert: closed once the hooks run
+	r.WaitShutdown()
+	waitStatus(t, StatusStopped)
+	select {
+	case <-started:
+	default:
+		t.Fatalf("not closed after the hooks ran")
+	}
+}
+
+func Test_WatchStatus(t *testing.T) {
+	// status is global; avoid parallel here
+	resetTriggerState(t)
+
+	t.Run("ok/multiple_watchers_notified", func(t *testing.T) {
+		// arrange
+		var a, b atomic.Int32
+		WatchStatus(t.Context(), func(Status) { a.Add(1) })
+		WatchStatus(t.Context(), func(Status) { b.Add(1) })
+
+		// act
+		setStatus(StatusDraining)
+		setStatus(StatusRunning)
+
+		// assert
+		assert.Eventually(t, func() bool { return a.Load() == 2 && b.Load() == 2 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("ok/unsubscribes_on_ctx_done", func(t *testing.T) {
+		// arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		WatchStatus(ctx, func(Status) {})
+
+		// act
+		cancel()
+
+		// assert
+		assert.Eventually(t, func() bool {
+			watchersMu.Lock()
+			defer watchersMu.Unlock()
+			return len(watchers) == 0
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func Test_Reset(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	var calls atomic.Int32
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+	userCh := make(chan struct{}, 1)
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil), WithUserChanSignal(userCh))
+
+	// act + assert: reset is possible only after shutdown
+	assert.ErrorIs(t, Reset(), ErrNotShutdown)
+
+	userCh <- struct{}{}
+	waitStatus(t, StatusStopped)
+	assert.NoError(t, Reset(WithKeepRegistrations()))
+
+	assert.Equal(t, StatusRunning, GetStatus())
+	assert.NoError(t, ShutdownCause())
+
+	// act + assert: trigger is re-armed
+	userCh <- struct{}{}
+	waitStatus(t, StatusStopped)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func Test_Reset_restartsWatchers(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	var calls atomic.Int32
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil), WithMaxLifetime(30*time.Millisecond, 0))
+	r.WaitShutdown()
+	waitStatus(t, StatusStopped)
+
+	// act
+	assert.NoError(t, Reset(WithKeepRegistrations()))
+
+	// assert
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+	waitStatus(t, StatusStopped)
+	assert.ErrorIs(t, ShutdownCause(), ErrMaxLifetime)
+}
+
+func Test_StatusHistory(t *testing.T) {
+	// status is global; avoid parallel here
+	resetTriggerState(t)
+
+	// act
+	setStatus(StatusDraining)
+	setStatus(StatusStopped)
+
+	// assert
+	h := StatusHistory()
+	assert.GreaterOrEqual(t, len(h), 3)
+	assert.Equal(t, StatusRunning, h[len(h)-3].Status)
+	assert.Equal(t, StatusDraining, h[len(h)-2].Status)
+	assert.Equal(t, StatusStopped, h[len(h)-1].Status)
+	assert.False(t, h[len(h)-1].At.Before(h[len(h)-2].At))
+
+	for i := 0; i < 2*maxStatusHistory; i++ {
+		setStatus(StatusRunning)
+	}
+	assert.Len(t, StatusHistory(), maxStatusHistory)
+}
+
+func Test_TriggerShutdown(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	cause := errors.New("fatal dependency error")
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil))
+
+	// act
+	TriggerShutdown(cause)
+
+	// assert
+	r.WaitShutdown()
+	assert.ErrorIs(t, ShutdownCause(), cause)
+	waitStatus(t, StatusStopped)
+}
+
+func Test_TriggerShutdown_lastTriggerOnly(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	var calls atomic.Int32
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil))
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil), WithPreStopDelay(time.Minute))
+
+	// act
+	TriggerShutdown(ErrUserTrigger)
+
+	// assert: handled by the last trigger, which waits for the delay
+	waitStatus(t, StatusDraining)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), calls.Load())
+	assert.NoError(t, AbortShutdown())
+}
+
+func Test_TriggerShutdown_droppedByReset(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	TriggerShutdown(ErrUserTrigger)
+
+	// act
+	resetTrigger()
+
+	// assert
+	manual.mu.Lock()
+	defer manual.mu.Unlock()
+	assert.Len(t, manual.ch, 0)
+}
+
+func Test_SetShutdownTrigger_watcherDuringShutdown(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	hookRunning := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+		close(hookRunning)
+		<-release
+		finished.Store(true)
+		return nil
+	}))
+
+	refire := make(chan struct{})
+	first := errors.New("first")
+	withWatcher := func(c *triggerConfig) {
+		c.watchers = append(c.watchers, func(ctx context.Context, fire func(cause error)) {
+			fire(first)
+			<-refire
+			fire(ErrIdleTimeout) // e.g. the in-flight work finished during the drain
+		})
+	}
+	SetShutdownTrigger(t.Context(), WithCustomSystemSignal(nil), withWatcher)
+	<-hookRunning
+
+	// act
+	close(refire)
+	TriggerShutdown(ErrServeFailed)
+	time.Sleep(50 * time.Millisecond) // would have forced exit by now
+	close(release)
+
+	// assert
+	r.WaitShutdown()
+	assert.True(t, finished.Load())
+	assert.ErrorIs(t, ShutdownCause(), first)
+	waitStatus(t, StatusStopped)
+}
@@ -0,0 +1,115 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lif0/pkg/utils/errx"
+)
+
+// Group runs named background goroutines (loops, consumers, batchers) with a
+// context that is canceled on shutdown.
+//
+// Group implements GracefulShutdownObject: register it once instead of a ctx/cancel
+// pair and a hook per goroutine.
+//
+// Use NewGroup to create a new instance.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	running map[string]int // name -> number of running goroutines
+	errs    errx.MultiError
+}
+
+// NewGroup creates and returns a new Group.
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Group{
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[string]int),
+	}
+}
+
+// Go runs f in a new goroutine. The ctx passed to f is canceled when the group
+// shuts down; f is expected to return soon after that.
+// An error returned by f is reported by GracefulShutdown, except context.Canceled
+// returned after the group has been canceled.
+//
+// Go returns ErrShutdownCalled, and does not run f, once the group is shutting down.
+func (g *Group) Go(name string, f func(ctx context.Context) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.ctx.Err() != nil {
+		return ErrShutdownCalled
+	}
+
+	g.running[name]++
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		err := f(g.ctx)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if g.running[name]--; g.running[name] == 0 {
+			delete(g.running, name)
+		}
+		if err != nil && !(g.ctx.Err() != nil && errors.Is(err, context.Canceled)) {
+			g.errs.Append(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+
+	return nil
+}
+
+// GracefulShutdown implements GracefulShutdownObject.
+// It cancels the goroutines' context and waits for them to return. It returns their
+// errors and, if ctx is done first, an error wrapping ErrGoroutinesRunning and ctx.Err()
+// with the names of the goroutines that have not returned.
+func (g *Group) GracefulShutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.cancel()
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	var ctxErr error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		ctxErr = ctx.Err()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	errs := append(errx.MultiError(nil), g.errs...)
+	if ctxErr != nil {
+		names := make([]string, 0, len(g.running))
+		for name := range g.running {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		errs.Append(fmt.Errorf("%w: %s: %w", ErrGoroutinesRunning, strings.Join(names, ", "), ctxErr))
+	}
+
+	return errs.MaybeUnwrap()
+}
@@ -0,0 +1,123 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/lif0/pkg/utils/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Group(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/cancelsAndWaits", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		g := gracefully.NewGroup()
+		exited := make(chan string, 2)
+		for _, name := range []string{"batcher", "consumer"} {
+			assert.NoError(t, g.Go(name, func(ctx context.Context) error {
+				<-ctx.Done()
+				exited <- name
+				return ctx.Err() // context.Canceled is not reported
+			}))
+		}
+
+		// act
+		err := g.GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, exited, 2)
+	})
+
+	t.Run("ok/reportsErrors", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("boom")
+		g := gracefully.NewGroup()
+		assert.NoError(t, g.Go("failing", func(ctx context.Context) error { return boom }))
+		assert.NoError(t, g.Go("flusher", func(ctx context.Context) error {
+			<-ctx.Done()
+			return errors.New("flush failed")
+		}))
+
+		// act
+		err := g.GracefulShutdown(context.Background())
+
+		// assert
+		var me errx.MultiError
+		assert.ErrorAs(t, err, &me)
+		assert.Len(t, me, 2)
+		assertMultiErrorContains(t, me, boom)
+		assert.ErrorContains(t, err, "failing: boom")
+		assert.ErrorContains(t, err, "flusher: flush failed")
+	})
+
+	t.Run("err/reportsNotExited", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		g := gracefully.NewGroup()
+		release := make(chan struct{})
+		defer close(release)
+		assert.NoError(t, g.Go("stuck", func(ctx context.Context) error {
+			<-release // ignores ctx
+			return nil
+		}))
+		assert.NoError(t, g.Go("polite", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := g.GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrGoroutinesRunning)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "did not exit: stuck:")
+		assert.NotContains(t, err.Error(), "polite")
+	})
+
+	t.Run("err/goAfterShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		g := gracefully.NewGroup()
+		assert.NoError(t, g.GracefulShutdown(context.Background()))
+
+		// act
+		err := g.Go("late", func(ctx context.Context) error { return nil })
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrShutdownCalled)
+	})
+
+	t.Run("ok/asRegisteredHook", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		g := gracefully.NewGroup()
+		r.MustRegister(g)
+		stopped := make(chan struct{})
+		assert.NoError(t, g.Go("loop", func(ctx context.Context) error {
+			<-ctx.Done()
+			close(stopped)
+			return nil
+		}))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		assert.Len(t, stopped, 0)
+		_, open := <-stopped
+		assert.False(t, open)
+	})
+}
@@ -0,0 +1,115 @@
+// Package health provides http.Handlers for Kubernetes-style probes driven by
+// the gracefully status:
+//
+//   - /readyz  - 200 while running, 503 once draining starts, so traffic is moved away.
+//   - /livez   - 200 until the shutdown has stopped (or a hook hangs), 503 afterwards.
+//   - /statusz - JSON with the status, its history and the registered hooks.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+)
+
+// Ready returns the readiness handler.
+// It responds 200 while the status is StatusRunning and 503 otherwise.
+func Ready(opts ...Option) http.Handler {
+	c := newDefaultConfig(opts...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.status() != gracefully.StatusRunning {
+			write(w, http.StatusServiceUnavailable, c.notReadyBody)
+			return
+		}
+		write(w, http.StatusOK, c.readyBody)
+	})
+}
+
+// Live returns the liveness handler.
+// It responds 200 until the status is StatusStopped, and 503 afterwards or when
+// a shutdown hook runs longer than WithHungHookTimeout.
+func Live(opts ...Option) http.Handler {
+	c := newDefaultConfig(opts...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.status() == gracefully.StatusStopped || c.hungHook() {
+			write(w, http.StatusServiceUnavailable, c.notLiveBody)
+			return
+		}
+		write(w, http.StatusOK, c.liveBody)
+	})
+}
+
+// StatusReport is the JSON document served by the Status handler.
+type StatusReport struct {
+	Status      string         `json:"status"`
+	Cause       string         `json:"cause,omitempty"`
+	History     []StatusChange `json:"history"`
+	Hooks       []string       `json:"hooks"`
+	RunningHook *RunningHook   `json:"running_hook,omitempty"`
+}
+
+// StatusChange is a status transition in StatusReport.
+type StatusChange struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// RunningHook is the shutdown hook currently running in StatusReport.
+type RunningHook struct {
+	Name  string    `json:"name"`
+	Since time.Time `json:"since"`
+}
+
+// Status returns the handler serving a StatusReport as JSON.
+func Status(opts ...Option) http.Handler {
+	c := newDefaultConfig(opts...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := StatusReport{
+			Status:  c.status().String(),
+			History: make([]StatusChange, 0),
+			Hooks:   c.registry.HookNames(),
+		}
+		if cause := gracefully.ShutdownCause(); cause != nil {
+			report.Cause = cause.Error()
+		}
+		for _, sc := range c.history() {
+			report.History = append(report.History, StatusChange{Status: sc.Status.String(), At: sc.At})
+		}
+		if name, since, ok := c.registry.RunningHook(); ok {
+			report.RunningHook = &RunningHook{Name: name, Since: since}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// Register registers the Ready, Live and Status handlers on mux
+// at /readyz, /livez and /statusz.
+func Register(mux *http.ServeMux, opts ...Option) {
+	mux.Handle("/readyz", Ready(opts...))
+	mux.Handle("/livez", Live(opts...))
+	mux.Handle("/statusz", Status(opts...))
+}
+
+// hungHook reports whether a shutdown hook runs longer than hungHookTimeout.
+func (c *config) hungHook() bool {
+	if c.hungHookTimeout <= 0 {
+		return false
+	}
+
+	_, since, ok := c.registry.RunningHook()
+	return ok && time.Since(since) > c.hungHookTimeout
+}
+
+func write(w http.ResponseWriter, code int, body []byte) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+func withStatus(s gracefully.Status) Option {
+	return func(c *config) {
+		c.status = func() gracefully.Status { return s }
+	}
+}
+
+func serve(h http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec
+}
+
+func Test_Ready(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/running", func(t *testing.T) {
+		t.Parallel()
+		// act
+		rec := serve(Ready(withStatus(gracefully.StatusRunning)))
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "ok", rec.Body.String())
+	})
+
+	t.Run("ok/draining_unavailable", func(t *testing.T) {
+		t.Parallel()
+		// act
+		rec := serve(Ready(withStatus(gracefully.StatusDraining), WithReadyBody("yes", "no")))
+		// assert
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "no", rec.Body.String())
+	})
+}
+
+func Test_Live(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/draining_still_live", func(t *testing.T) {
+		t.Parallel()
+		// act
+		rec := serve(Live(withStatus(gracefully.StatusDraining), WithRegistry(gracefully.NewRegistry())))
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("ok/stopped_unavailable", func(t *testing.T) {
+		t.Parallel()
+		// act
+		rec := serve(Live(withStatus(gracefully.StatusStopped), WithLiveBody("alive", "dead")))
+		// assert
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "dead", rec.Body.String())
+	})
+
+	t.Run("ok/hung_hook_unavailable", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		release := make(chan struct{})
+		assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+			<-release
+			return nil
+		}))
+		go r.Shutdown(context.Background())
+		h := Live(withStatus(gracefully.StatusDraining), WithRegistry(r), WithHungHookTimeout(20*time.Millisecond))
+
+		// act + assert
+		assert.Equal(t, http.StatusOK, serve(h).Code)
+		assert.Eventually(t, func() bool {
+			return serve(h).Code == http.StatusServiceUnavailable
+		}, time.Second, 5*time.Millisecond)
+
+		close(release)
+		r.WaitShutdown()
+		assert.Equal(t, http.StatusOK, serve(h).Code)
+	})
+}
+
+func Test_Status(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/json_report", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		assert.NoError(t, r.RegisterFunc(func(context.Context) error { return nil }))
+		at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		history := func(c *config) {
+			c.history = func() []gracefully.StatusChange {
+				return []gracefully.StatusChange{{Status: gracefully.StatusRunning, At: at}}
+			}
+		}
+
+		// act
+		rec := serve(Status(withStatus(gracefully.StatusRunning), WithRegistry(r), history))
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var report StatusReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, "Running", report.Status)
+		assert.Equal(t, []StatusChange{{Status: "Running", At: at}}, report.History)
+		assert.Len(t, report.Hooks, 1)
+		assert.Contains(t, report.Hooks[0], "Test_Status")
+		assert.Nil(t, report.RunningHook)
+	})
+}
+
+func Test_Register(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/routes", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		mux := http.NewServeMux()
+		Register(mux, withStatus(gracefully.StatusRunning), WithRegistry(gracefully.NewRegistry()))
+
+		// act + assert
+		for _, path := range []string{"/readyz", "/livez", "/statusz"} {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+		}
+	})
+}
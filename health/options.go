@@ -0,0 +1,77 @@
+package health
+
+import (
+	"time"
+
+	"github.com/lif0/go-gracefully"
+)
+
+// config represents the configuration of the health handlers.
+type config struct {
+	registry *gracefully.Registry
+
+	readyBody, notReadyBody []byte
+	liveBody, notLiveBody   []byte
+
+	hungHookTimeout time.Duration
+
+	// overridable in tests
+	status  func() gracefully.Status
+	history func() []gracefully.StatusChange
+}
+
+type Option func(*config)
+
+// WithRegistry sets the registry used to report hook names and detect hung hooks.
+// By default, the global registry is used.
+func WithRegistry(r *gracefully.Registry) Option {
+	return func(c *config) {
+		c.registry = r
+	}
+}
+
+// WithReadyBody sets the response bodies of the readiness handler
+// for the ready (200) and not ready (503) cases.
+func WithReadyBody(ready, notReady string) Option {
+	return func(c *config) {
+		c.readyBody = []byte(ready)
+		c.notReadyBody = []byte(notReady)
+	}
+}
+
+// WithLiveBody sets the response bodies of the liveness handler
+// for the live (200) and not live (503) cases.
+func WithLiveBody(live, notLive string) Option {
+	return func(c *config) {
+		c.liveBody = []byte(live)
+		c.notLiveBody = []byte(notLive)
+	}
+}
+
+// WithHungHookTimeout makes the liveness handler fail once a shutdown hook has been
+// running for longer than d, so the orchestrator restarts a process stuck in shutdown.
+// By default, hung hooks are not detected.
+func WithHungHookTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.hungHookTimeout = d
+	}
+}
+
+// newDefaultConfig create default config
+func newDefaultConfig(opts ...Option) *config {
+	c := &config{
+		status:  gracefully.GetStatus,
+		history: gracefully.StatusHistory,
+	}
+	WithReadyBody("ok", "shutting down")(c)
+	WithLiveBody("ok", "stopped")(c)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.registry == nil {
+		c.registry = gracefully.GlobalRegistry()
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// triggerShutdown is TriggerShutdown, replaced in tests.
+var triggerShutdown = TriggerShutdown
+
+// httpServer adapts *http.Server to GracefulShutdownObject.
+type httpServer struct {
+	srv *http.Server
+}
+
+// HTTPServer returns a GracefulShutdownObject that shuts srv down gracefully:
+// keep-alives are disabled, srv.Shutdown waits for active connections, and once
+// ctx is done the remaining connections are closed with srv.Close.
+//
+// Keep the returned value if you need to Unregister it later.
+//
+// Example:
+//
+//	srv := &http.Server{Addr: ":8080", Handler: mux}
+//	gracefully.MustRegister(gracefully.HTTPServer(srv))
+//	go gracefully.ListenAndServe(srv)
+func HTTPServer(srv *http.Server) GracefulShutdownObject {
+	return &httpServer{srv: srv}
+}
+
+// GracefulShutdown implements GracefulShutdownObject.
+func (s *httpServer) GracefulShutdown(ctx context.Context) error {
+	s.srv.SetKeepAlivesEnabled(false)
+
+	if err := s.srv.Shutdown(ctx); err != nil {
+		closeErr := s.srv.Close()
+		return fmt.Errorf("http server %s: %w", s.srv.Addr, errors.Join(err, closeErr))
+	}
+	return nil
+}
+
+// ListenAndServe runs srv.ListenAndServe. When the server is shut down it returns nil;
+// any other failure (e.g. the address is in use) starts graceful shutdown with
+// ErrServeFailed as the cause (see TriggerShutdown) and is returned.
+func ListenAndServe(srv *http.Server) error {
+	return serveResult(srv, srv.ListenAndServe())
+}
+
+// Serve works like ListenAndServe, but accepts connections on ln (see http.Server.Serve).
+func Serve(srv *http.Server, ln net.Listener) error {
+	return serveResult(srv, srv.Serve(ln))
+}
+
+func serveResult(srv *http.Server, err error) error {
+	if err == nil || errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	err = fmt.Errorf("%w: http server %s: %w", ErrServeFailed, srv.Addr, err)
+	triggerShutdown(err)
+	return err
+}
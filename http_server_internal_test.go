@@ -0,0 +1,125 @@
+package gracefully
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startServer serves handler on a random local port.
+func startServer(t *testing.T, handler http.HandlerFunc) (*http.Server, <-chan error) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: handler}
+	served := make(chan error, 1)
+	go func() { served <- Serve(srv, ln) }()
+
+	return srv, served
+}
+
+func Test_HTTPServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/waitsForActiveRequest", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		entered, release := make(chan struct{}), make(chan struct{})
+		srv, served := startServer(t, func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-release
+		})
+		go func() { _, _ = http.Get("http://" + srv.Addr) }()
+		<-entered
+		shut := make(chan error, 1)
+
+		// act
+		go func() { shut <- HTTPServer(srv).GracefulShutdown(context.Background()) }()
+
+		// assert
+		select {
+		case <-shut:
+			t.Fatalf("shutdown must wait for the active request")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+		assert.NoError(t, <-shut)
+		assert.NoError(t, <-served)
+	})
+
+	t.Run("err/deadlineClosesConnections", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		entered, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		srv, served := startServer(t, func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-release
+		})
+		reqErr := make(chan error, 1)
+		go func() {
+			_, err := http.Get("http://" + srv.Addr)
+			reqErr <- err
+		}()
+		<-entered
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := HTTPServer(srv).GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, srv.Addr)
+		assert.Error(t, <-reqErr, "connection must be closed after the deadline")
+		assert.NoError(t, <-served)
+	})
+}
+
+func Test_Serve(t *testing.T) {
+	// triggerShutdown is global state; avoid parallel here
+	triggered := make(chan error, 1)
+	triggerShutdown = func(cause error) { triggered <- cause }
+	t.Cleanup(func() { triggerShutdown = TriggerShutdown })
+
+	t.Run("err/failureTriggersShutdown", func(t *testing.T) {
+		// arrange
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		_ = ln.Close()
+		srv := &http.Server{Addr: ln.Addr().String()}
+
+		// act
+		err = Serve(srv, ln)
+
+		// assert
+		assert.ErrorIs(t, err, ErrServeFailed)
+		select {
+		case cause := <-triggered:
+			assert.ErrorIs(t, cause, ErrServeFailed)
+		default:
+			t.Fatalf("serve failure must trigger shutdown")
+		}
+	})
+
+	t.Run("err/listenFailureTriggersShutdown", func(t *testing.T) {
+		// arrange
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer ln.Close()
+		srv := &http.Server{Addr: ln.Addr().String()} // address in use
+
+		// act
+		err = ListenAndServe(srv)
+
+		// assert
+		assert.ErrorIs(t, err, ErrServeFailed)
+		assert.ErrorIs(t, <-triggered, ErrServeFailed)
+	})
+}
@@ -0,0 +1,149 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubComponent struct {
+	name     string
+	startErr error
+	log      *[]string
+}
+
+func (c *stubComponent) Start(context.Context) error {
+	*c.log = append(*c.log, "start "+c.name)
+	return c.startErr
+}
+
+func (c *stubComponent) GracefulShutdown(context.Context) error {
+	*c.log = append(*c.log, "stop "+c.name)
+	return nil
+}
+
+func Test_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/startsInOrder_and_shutsDownAll", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var log []string
+		r := gracefully.NewRegistry()
+		r.MustRegister(&stubComponent{name: "db", log: &log}, &stubComponent{name: "http", log: &log})
+		assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+			log = append(log, "stop func")
+			return nil
+		}))
+
+		// act
+		startErrs := r.Start(context.Background())
+		stopErrs := r.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, startErrs.IsEmpty())
+		assert.True(t, stopErrs.IsEmpty())
+		assert.Equal(t, []string{"start db", "start http", "stop db", "stop http", "stop func"}, log)
+	})
+
+	t.Run("err/rollsBackStartedInReverse", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var log []string
+		boom := errors.New("port in use")
+		r := gracefully.NewRegistry()
+		r.MustRegister(
+			&stubComponent{name: "db", log: &log},
+			&stubComponent{name: "cache", log: &log},
+			&stubComponent{name: "http", log: &log, startErr: boom},
+			&stubComponent{name: "consumer", log: &log},
+		)
+
+		// act
+		errs := r.Start(context.Background())
+
+		// assert
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], boom)
+		assert.ErrorContains(t, errs[0], "*gracefully_test.stubComponent")
+		assert.Equal(t, []string{"start db", "start cache", "start http", "stop cache", "stop db"}, log)
+
+		// act: rolled back and never started components are skipped by Shutdown
+		log = nil
+		assert.True(t, r.Shutdown(context.Background()).IsEmpty())
+
+		// assert
+		assert.Empty(t, log)
+	})
+
+	t.Run("ok/childStartedAtPosition", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var log []string
+		r := gracefully.NewRegistry()
+		r.MustRegister(&stubComponent{name: "db", log: &log})
+		child, err := r.NewChild("http")
+		assert.NoError(t, err)
+		child.MustRegister(&stubComponent{name: "server", log: &log})
+		r.MustRegister(&stubComponent{name: "consumer", log: &log, startErr: errors.New("boom")})
+
+		// act
+		errs := r.Start(context.Background())
+
+		// assert
+		assert.Len(t, errs, 1)
+		assert.Equal(t, []string{"start db", "start server", "start consumer", "stop server", "stop db"}, log)
+	})
+
+	t.Run("ok/newChildAfterStart_isShutDown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var log []string
+		r := gracefully.NewRegistry()
+		r.MustRegister(&stubComponent{name: "db", log: &log})
+		assert.True(t, r.Start(context.Background()).IsEmpty())
+		child, err := r.NewChild("late")
+		assert.NoError(t, err)
+		assert.NoError(t, child.RegisterFunc(func(context.Context) error {
+			log = append(log, "stop late")
+			return nil
+		}))
+		r.MustRegister(&stubComponent{name: "cache", log: &log})
+
+		// act
+		errs := r.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, errs.IsEmpty())
+		assert.Equal(t, []string{"start db", "stop db", "stop late", "stop cache"}, log)
+	})
+
+	t.Run("err/twice", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		assert.True(t, r.Start(context.Background()).IsEmpty())
+
+		// act
+		errs := r.Start(context.Background())
+
+		// assert
+		assertMultiErrorContains(t, errs, gracefully.ErrStartCalled)
+	})
+
+	t.Run("err/afterShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		_ = r.Shutdown(context.Background())
+
+		// act
+		errs := r.Start(context.Background())
+
+		// assert
+		assertMultiErrorContains(t, errs, gracefully.ErrShutdownCalled)
+	})
+}
@@ -0,0 +1,187 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// TrackingListener is a net.Listener that stops accepting connections as soon as the
+// shutdown started by the trigger runs its hooks, and tracks the connections it has
+// accepted. It keeps accepting during the pre-stop delay (see WithPreStopDelay),
+// so an aborted shutdown leaves it open.
+//
+// TrackingListener implements GracefulShutdownObject: it waits for the tracked
+// connections to be closed, and closes the remaining ones once the shutdown
+// context is done.
+//
+// Use Listener to create a new instance.
+type TrackingListener struct {
+	net.Listener
+	cfg *listenerConfig
+
+	stopWatch context.CancelFunc
+	closeOnce sync.Once
+	closeErr  error
+
+	mu    sync.Mutex
+	conns map[*trackedConn]struct{}
+	idle  chan struct{} // closed while there are no tracked connections
+}
+
+// Listener wraps ln into a TrackingListener.
+//
+// Example:
+//
+//	ln, err := net.Listen("tcp", ":9000")
+//	...
+//	tln := gracefully.Listener(ln, gracefully.WithShutdownReadDeadline(time.Second))
+//	gracefully.MustRegister(tln)
+//	for {
+//		conn, err := tln.Accept()
+//		if err != nil {
+//			return // closed on shutdown
+//		}
+//		go handle(conn)
+//	}
+func Listener(ln net.Listener, opts ...ListenerOption) *TrackingListener {
+	return newListener(ln, hooksStarted(), opts...)
+}
+
+func newListener(ln net.Listener, started <-chan struct{}, opts ...ListenerOption) *TrackingListener {
+	c := newDefaultListenerConfig()
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	idle := make(chan struct{})
+	close(idle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &TrackingListener{
+		Listener:  ln,
+		cfg:       c,
+		stopWatch: cancel,
+		conns:     map[*trackedConn]struct{}{},
+		idle:      idle,
+	}
+
+	go func() {
+		select {
+		case <-started:
+			_ = l.Close()
+		case <-ctx.Done():
+		}
+	}()
+	return l
+}
+
+// Accept implements net.Listener. The returned connection is tracked until it is closed.
+func (l *TrackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &trackedConn{Conn: conn, l: l}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.conns) == 0 {
+		l.idle = make(chan struct{})
+	}
+	l.conns[tc] = struct{}{}
+
+	return tc, nil
+}
+
+// Close implements net.Listener. It stops accepting connections; the tracked
+// connections are left open. Calling it more than once is a no-op.
+func (l *TrackingListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.stopWatch()
+		l.closeErr = l.Listener.Close()
+	})
+	return l.closeErr
+}
+
+// Active returns the number of tracked connections.
+func (l *TrackingListener) Active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.conns)
+}
+
+// GracefulShutdown implements GracefulShutdownObject.
+// It closes the listener and waits for the tracked connections to be closed.
+// If ctx is done first, the remaining connections are closed and an error wrapping
+// ErrConnectionsInUse and ctx.Err() reports how many there were.
+func (l *TrackingListener) GracefulShutdown(ctx context.Context) error {
+	closeErr := l.Close()
+	if errors.Is(closeErr, net.ErrClosed) {
+		closeErr = nil
+	}
+
+	l.mu.Lock()
+	idle := l.idle
+	if l.cfg.readDeadline > 0 {
+		deadline := time.Now().Add(l.cfg.readDeadline)
+		for tc := range l.conns {
+			_ = tc.SetReadDeadline(deadline)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-idle:
+		return closeErr
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	conns := make([]*trackedConn, 0, len(l.conns))
+	for tc := range l.conns {
+		conns = append(conns, tc)
+	}
+	l.mu.Unlock()
+
+	if len(conns) == 0 {
+		return closeErr
+	}
+	for _, tc := range conns {
+		_ = tc.Close()
+	}
+	err := fmt.Errorf("%w: %d connection(s): %w", ErrConnectionsInUse, len(conns), ctx.Err())
+	return errors.Join(err, closeErr)
+}
+
+func (l *TrackingListener) forget(tc *trackedConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.conns[tc]; !ok {
+		return
+	}
+	delete(l.conns, tc)
+	if len(l.conns) == 0 {
+		close(l.idle)
+	}
+}
+
+// trackedConn is a connection accepted by TrackingListener.
+type trackedConn struct {
+	net.Conn
+	l *TrackingListener
+}
+
+// Close implements net.Conn.
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.l.forget(c)
+	return err
+}
@@ -0,0 +1,146 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestListener returns a TrackingListener on a local port that doesn't depend on
+// the global trigger, and a function that reports the start of the shutdown hooks to it.
+func newTestListener(t *testing.T, opts ...ListenerOption) (*TrackingListener, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	started := make(chan struct{})
+	l := newListener(ln, started, opts...)
+	t.Cleanup(func() { _ = l.Close() })
+
+	return l, func() { close(started) }
+}
+
+// dialAccept connects to l and returns the accepted server side connection.
+func dialAccept(t *testing.T, l *TrackingListener) (server, client net.Conn) {
+	client, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	server, err = l.Accept()
+	assert.NoError(t, err)
+	return server, client
+}
+
+func Test_TrackingListener(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/closesWhenHooksStart", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l, hooksStart := newTestListener(t)
+		server, _ := dialAccept(t, l) // accepting until then, e.g. during the pre-stop delay
+		assert.NoError(t, server.Close())
+
+		// act
+		hooksStart()
+		_, err := l.Accept()
+
+		// assert
+		assert.ErrorIs(t, err, net.ErrClosed)
+	})
+
+	t.Run("ok/closesOnShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l, _ := newTestListener(t)
+
+		// act
+		err := l.GracefulShutdown(context.Background())
+		_, acceptErr := l.Accept()
+
+		// assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, acceptErr, net.ErrClosed)
+	})
+
+	t.Run("ok/tracksConnections", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l, _ := newTestListener(t)
+		server, _ := dialAccept(t, l)
+		assert.Equal(t, 1, l.Active())
+
+		// act
+		assert.NoError(t, server.Close())
+		_ = server.Close() // forgetting twice is a no-op
+
+		// assert
+		assert.Equal(t, 0, l.Active())
+	})
+
+	t.Run("ok/waitsForConnections", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l, _ := newTestListener(t)
+		server, _ := dialAccept(t, l)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			server.Close()
+		}()
+
+		// act
+		err := l.GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, l.Active())
+	})
+
+	t.Run("ok/nudgesWithReadDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l, _ := newTestListener(t, WithShutdownReadDeadline(10*time.Millisecond))
+		server, _ := dialAccept(t, l)
+		go func() {
+			// an idle handler: blocked in Read until the deadline wakes it up
+			_, err := server.Read(make([]byte, 1))
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				server.Close()
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// act
+		err := l.GracefulShutdown(ctx)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("err/forceClosesAtDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l, _ := newTestListener(t)
+		dialAccept(t, l)
+		_, client := dialAccept(t, l)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := l.GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, ErrConnectionsInUse)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, ": 2 connection(s)")
+		assert.Equal(t, 0, l.Active())
+		_ = client.SetReadDeadline(time.Now().Add(time.Second))
+		_, readErr := client.Read(make([]byte, 1))
+		assert.Error(t, readErr, "server side is closed")
+	})
+}
@@ -0,0 +1,25 @@
+package gracefully
+
+import "time"
+
+// listenerConfig represents the configuration for Listener.
+type listenerConfig struct {
+	readDeadline time.Duration
+}
+
+type ListenerOption func(*listenerConfig)
+
+// WithShutdownReadDeadline makes TrackingListener.GracefulShutdown set the read
+// deadline of the tracked connections to now+d, so connections blocked in Read
+// (e.g. idle keep-alive connections) wake up and can be closed by their handlers.
+// Default: off.
+func WithShutdownReadDeadline(d time.Duration) ListenerOption {
+	return func(c *listenerConfig) {
+		c.readDeadline = d
+	}
+}
+
+// newDefaultListenerConfig create default config
+func newDefaultListenerConfig() *listenerConfig {
+	return &listenerConfig{}
+}
@@ -0,0 +1,138 @@
+package gracefully
+
+import (
+	"os"
+	"path"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	memorySourceCgroup  = "cgroup"
+	memorySourceRuntime = "runtime"
+)
+
+// ownCgroupMemoryFiles returns the files reporting the memory usage of the cgroup
+// of the process, resolved once from /proc/self.
+var ownCgroupMemoryFiles = sync.OnceValue(func() []string {
+	cgroups, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return nil
+	}
+	mountinfo, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return nil
+	}
+	return cgroupMemoryFiles(string(cgroups), string(mountinfo))
+})
+
+// runtimeMemoryMetrics are the runtime/metrics samples used when no cgroup file is available.
+// The memory mapped by the Go runtime is total minus released back to the OS.
+var runtimeMemoryMetrics = []metrics.Sample{
+	{Name: "/memory/classes/total:bytes"},
+	{Name: "/memory/classes/heap/released:bytes"},
+}
+
+// sampleMemory returns the current memory usage in bytes and where it was taken from.
+// The cgroup usage is preferred because that is what the OOM killer looks at.
+func sampleMemory() (uint64, string) {
+	for _, path := range ownCgroupMemoryFiles() {
+		if used, ok := readCgroupMemory(path); ok {
+			return used, memorySourceCgroup
+		}
+	}
+
+	samples := make([]metrics.Sample, len(runtimeMemoryMetrics))
+	copy(samples, runtimeMemoryMetrics)
+	metrics.Read(samples)
+
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64(), memorySourceRuntime
+}
+
+// readCgroupMemory reads a cgroup memory usage file.
+func readCgroupMemory(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	used, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return used, true
+}
+
+// cgroupMemoryFiles lists the files reporting the memory usage of the cgroup described
+// by cgroups (the content of /proc/self/cgroup), v2 first, then v1. mountinfo is the
+// content of /proc/self/mountinfo, used to find where each hierarchy is mounted.
+//
+// The v1 root cgroup is skipped: without a cgroup namespace it accounts for the whole
+// host, not for the process. (The v2 root cgroup has no memory.current.)
+func cgroupMemoryFiles(cgroups, mountinfo string) []string {
+	var v2, v1 string // cgroup paths of the process
+	for _, line := range strings.Split(cgroups, "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[0] == "0" && parts[1] == "":
+			v2 = parts[2]
+		case hasField(parts[1], ",", "memory"):
+			v1 = parts[2]
+		}
+	}
+
+	var files []string
+	for _, line := range strings.Split(mountinfo, "\n") {
+		// ID parentID major:minor root mountpoint options [optional...] - fstype source superoptions
+		pre, post, ok := strings.Cut(line, " - ")
+		if !ok {
+			continue
+		}
+		preFields, postFields := strings.Fields(pre), strings.Fields(post)
+		if len(preFields) < 5 || len(postFields) < 3 {
+			continue
+		}
+		root, mountpoint := preFields[3], preFields[4]
+
+		switch {
+		case postFields[0] == "cgroup2" && v2 != "":
+			if rel, ok := cgroupRel(v2, root); ok {
+				files = append([]string{path.Join(mountpoint, rel, "memory.current")}, files...)
+			}
+		case postFields[0] == "cgroup" && v1 != "" && v1 != "/" && hasField(postFields[2], ",", "memory"):
+			if rel, ok := cgroupRel(v1, root); ok {
+				files = append(files, path.Join(mountpoint, rel, "memory.usage_in_bytes"))
+			}
+		}
+	}
+	return files
+}
+
+// cgroupRel returns the path of cgroup relative to the root of its mount,
+// or false if cgroup is not visible in that mount.
+func cgroupRel(cgroup, root string) (string, bool) {
+	if root == "/" {
+		return cgroup, true
+	}
+	if cgroup != root && !strings.HasPrefix(cgroup, root+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(cgroup, root), true
+}
+
+// hasField reports whether the sep-separated list contains field.
+func hasField(list, sep, field string) bool {
+	for _, f := range strings.Split(list, sep) {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
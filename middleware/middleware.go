@@ -0,0 +1,82 @@
+// Package middleware provides a net/http middleware that drains requests
+// during graceful shutdown.
+//
+// Once the status is Draining, new requests are rejected with 503, Retry-After and
+// Connection: close, while requests already in flight are allowed to finish.
+// The Middleware is registered as a shutdown hook that waits for them.
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/lif0/go-gracefully"
+)
+
+// Middleware tracks in-flight requests and rejects new ones during shutdown.
+//
+// Use New to create a new instance.
+type Middleware struct {
+	cfg     *config
+	tracker *gracefully.Tracker
+}
+
+// New creates a Middleware and registers it as a shutdown hook.
+func New(opts ...Option) (*Middleware, error) {
+	m := &Middleware{
+		cfg:     newDefaultConfig(opts...),
+		tracker: gracefully.NewTracker(),
+	}
+
+	if err := m.cfg.registerer.Register(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Wrap returns a handler that serves requests with next until shutdown starts.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.cfg.status() != gracefully.StatusRunning {
+			m.reject(w)
+			return
+		}
+
+		ok, release := m.tracker.Acquire()
+		if !ok {
+			m.reject(w)
+			return
+		}
+		defer release()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// InFlight returns the number of requests currently being served.
+func (m *Middleware) InFlight() int {
+	return m.tracker.Active()
+}
+
+// GracefulShutdown implements gracefully.GracefulShutdownObject.
+// It stops admitting requests and waits for the in-flight ones to finish.
+// If ctx is done first, the returned error reports how many are still in flight.
+func (m *Middleware) GracefulShutdown(ctx context.Context) error {
+	if err := m.tracker.GracefulShutdown(ctx); err != nil {
+		return fmt.Errorf("middleware: %w", err)
+	}
+	return nil
+}
+
+func (m *Middleware) reject(w http.ResponseWriter) {
+	retryAfter := int(math.Ceil(m.cfg.retryAfter.Seconds()))
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("Connection", "close")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write(m.cfg.rejectBody)
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+func withStatus(s *atomic.Uint32) Option {
+	return func(c *config) {
+		c.status = func() gracefully.Status { return gracefully.Status(s.Load()) }
+	}
+}
+
+func serve(h http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("OK"))
+})
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/registersHook", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+
+		// act
+		m, err := New(WithRegisterer(r))
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, r.Unregister(m))
+	})
+
+	t.Run("err/registryShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		_ = r.Shutdown(context.Background())
+
+		// act
+		m, err := New(WithRegisterer(r))
+
+		// assert
+		assert.Nil(t, m)
+		assert.ErrorIs(t, err, gracefully.ErrShutdownCalled)
+	})
+}
+
+func Test_Wrap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/servesWhileRunning", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var status atomic.Uint32
+		m, err := New(WithRegisterer(gracefully.NewRegistry()), withStatus(&status))
+		assert.NoError(t, err)
+
+		// act
+		rec := serve(m.Wrap(okHandler))
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "OK", rec.Body.String())
+		assert.Equal(t, 0, m.InFlight())
+	})
+
+	t.Run("ok/rejectsWhileDraining", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var status atomic.Uint32
+		status.Store(uint32(gracefully.StatusDraining))
+		m, err := New(
+			WithRegisterer(gracefully.NewRegistry()),
+			withStatus(&status),
+			WithRetryAfter(1500*time.Millisecond),
+			WithRejectBody("bye"),
+		)
+		assert.NoError(t, err)
+
+		// act
+		rec := serve(m.Wrap(okHandler))
+
+		// assert
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "close", rec.Header().Get("Connection"))
+		assert.Equal(t, "bye", rec.Body.String())
+	})
+}
+
+func Test_GracefulShutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/waitsForInFlight", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var status atomic.Uint32
+		r := gracefully.NewRegistry()
+		m, err := New(WithRegisterer(r), withStatus(&status))
+		assert.NoError(t, err)
+		entered, release := make(chan struct{}), make(chan struct{})
+		h := m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-release
+		}))
+		go serve(h)
+		<-entered
+		done := make(chan struct{})
+
+		// act
+		go func() {
+			_ = r.Shutdown(context.Background())
+			close(done)
+		}()
+
+		// assert
+		assert.Eventually(t, func() bool {
+			return serve(m.Wrap(okHandler)).Code == http.StatusServiceUnavailable
+		}, time.Second, time.Millisecond, "new requests must be rejected once the hook runs")
+		select {
+		case <-done:
+			t.Fatalf("shutdown must wait for the in-flight request")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+		<-done
+		assert.Equal(t, 0, m.InFlight())
+	})
+
+	t.Run("err/deadlineReportsInFlight", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var status atomic.Uint32
+		m, err := New(WithRegisterer(gracefully.NewRegistry()), withStatus(&status))
+		assert.NoError(t, err)
+		entered, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		go serve(m.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(entered)
+			<-release
+		})))
+		<-entered
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err = m.GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, gracefully.ErrWorkAbandoned)
+		assert.ErrorContains(t, err, "1 unit(s)")
+	})
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/lif0/go-gracefully"
+)
+
+// config represents the configuration of the Middleware.
+type config struct {
+	registerer gracefully.Registerer
+
+	retryAfter time.Duration
+	rejectBody []byte
+
+	// overridable in tests
+	status func() gracefully.Status
+}
+
+type Option func(*config)
+
+// WithRegisterer sets the registry the Middleware registers its shutdown hook in.
+// By default, the gracefully.DefaultRegisterer is used.
+func WithRegisterer(r gracefully.Registerer) Option {
+	return func(c *config) {
+		c.registerer = r
+	}
+}
+
+// WithRetryAfter sets the Retry-After header sent with rejected requests.
+// It is rounded up to whole seconds. By default, it is 5 seconds.
+func WithRetryAfter(d time.Duration) Option {
+	return func(c *config) {
+		c.retryAfter = d
+	}
+}
+
+// WithRejectBody sets the response body of rejected requests.
+func WithRejectBody(body string) Option {
+	return func(c *config) {
+		c.rejectBody = []byte(body)
+	}
+}
+
+// newDefaultConfig create default config
+func newDefaultConfig(opts ...Option) *config {
+	c := &config{
+		registerer: gracefully.DefaultRegisterer,
+		status:     gracefully.GetStatus,
+	}
+	WithRetryAfter(5 * time.Second)(c)
+	WithRejectBody("service is shutting down, try again later")(c)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
@@ -0,0 +1,132 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Process supervises a child process started from an *exec.Cmd.
+//
+// On shutdown the process is sent the stop signal (see WithStopSignal) and given
+// the grace period (see WithGracePeriod) to exit; after that, or once the shutdown
+// context is done, it is killed. On unix the process runs in its own process group
+// and the whole group is signaled, so helpers spawned by the child don't outlive it either.
+//
+// Process implements Starter, so Registry.Start starts it in registration order.
+//
+// Example:
+//
+//	proc := gracefully.NewProcess(exec.Command("./sidecar"), gracefully.WithGracePeriod(5*time.Second))
+//	gracefully.MustRegister(proc)
+//	if errs := gracefully.Start(ctx); !errs.IsEmpty() {
+//		log.Fatal(errs)
+//	}
+type Process struct {
+	cfg *processConfig
+	cmd *exec.Cmd
+
+	mu      sync.Mutex
+	started bool
+	done    chan struct{} // closed when the process has exited
+	err     error         // result of cmd.Wait, set before done is closed
+}
+
+// NewProcess returns a Process for cmd. cmd must not be started yet; use Process.Start.
+func NewProcess(cmd *exec.Cmd, opts ...ProcessOption) *Process {
+	c := newDefaultProcessConfig()
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	setProcessGroup(cmd)
+	return &Process{cfg: c, cmd: cmd, done: make(chan struct{})}
+}
+
+// Start implements Starter. It starts the process and returns without waiting for it;
+// use Wait or Done to learn when it exits.
+func (p *Process) Start(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return ErrStartCalled
+	}
+	if err := p.cmd.Start(); err != nil {
+		return fmt.Errorf("process %s: %w", p.cmd.Path, err)
+	}
+	p.started = true
+
+	go func() {
+		p.err = p.cmd.Wait()
+		close(p.done)
+	}()
+	return nil
+}
+
+// Done returns a channel that is closed when the process has exited.
+func (p *Process) Done() <-chan struct{} { return p.done }
+
+// Wait blocks until the process has exited and returns the result of exec.Cmd.Wait.
+func (p *Process) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// GracefulShutdown implements GracefulShutdownObject. It returns nil if the process
+// was never started, or exited successfully or because of the stop signal.
+// Otherwise the exit status is reported; if the process had to be killed,
+// the error wraps ErrProcessKilled.
+func (p *Process) GracefulShutdown(ctx context.Context) error {
+	p.mu.Lock()
+	started := p.started
+	p.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	select {
+	case <-p.done:
+		return p.exitError(false)
+	default:
+	}
+
+	if err := signalProcess(p.cmd.Process, p.cfg.stopSignal); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		// the stop signal is not supported (e.g. os.Interrupt on windows), kill right away
+		return p.kill()
+	}
+
+	t := time.NewTimer(p.cfg.gracePeriod)
+	defer t.Stop()
+
+	select {
+	case <-p.done:
+		return p.exitError(false)
+	case <-t.C:
+	case <-ctx.Done():
+	}
+	return p.kill()
+}
+
+func (p *Process) kill() error {
+	_ = killProcess(p.cmd.Process)
+	<-p.done
+	return p.exitError(true)
+}
+
+// exitError reports how the process exited.
+func (p *Process) exitError(killed bool) error {
+	name := fmt.Sprintf("process %s (pid %d)", p.cmd.Path, p.cmd.Process.Pid)
+	if killed {
+		return fmt.Errorf("%w: %s: %s", ErrProcessKilled, name, p.cmd.ProcessState)
+	}
+	if p.err == nil || terminatedBy(p.cmd.ProcessState, p.cfg.stopSignal) {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", name, p.err)
+}
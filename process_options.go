@@ -0,0 +1,38 @@
+package gracefully
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// processConfig represents the configuration for NewProcess.
+type processConfig struct {
+	stopSignal  os.Signal
+	gracePeriod time.Duration
+}
+
+type ProcessOption func(*processConfig)
+
+// WithStopSignal sets the signal sent to the process on shutdown. Default: SIGTERM.
+func WithStopSignal(sig os.Signal) ProcessOption {
+	return func(c *processConfig) {
+		c.stopSignal = sig
+	}
+}
+
+// WithGracePeriod sets how long the process is given to exit after the stop signal
+// before it is killed. The shutdown context may cut it shorter. Default: 10s.
+func WithGracePeriod(d time.Duration) ProcessOption {
+	return func(c *processConfig) {
+		c.gracePeriod = d
+	}
+}
+
+// newDefaultProcessConfig create default config
+func newDefaultProcessConfig() *processConfig {
+	c := &processConfig{}
+	WithStopSignal(syscall.SIGTERM)(c)
+	WithGracePeriod(10 * time.Second)(c)
+	return c
+}
@@ -0,0 +1,25 @@
+//go:build !unix
+
+package gracefully
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op: process groups are not supported on this platform.
+func setProcessGroup(*exec.Cmd) {}
+
+// signalProcess sends sig to p.
+func signalProcess(p *os.Process, sig os.Signal) error {
+	return p.Signal(sig)
+}
+
+// killProcess kills p.
+func killProcess(p *os.Process) error {
+	return p.Kill()
+}
+
+// terminatedBy reports whether the process was terminated by sig;
+// always false on this platform.
+func terminatedBy(*os.ProcessState, os.Signal) bool { return false }
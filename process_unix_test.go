@@ -0,0 +1,140 @@
+//go:build unix
+
+package gracefully_test
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Process(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/stopsWithSignal", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		p := gracefully.NewProcess(exec.Command("sleep", "10"))
+		assert.NoError(t, p.Start(context.Background()))
+
+		// act
+		err := p.GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Len(t, p.Done(), 0)
+		_, open := <-p.Done()
+		assert.False(t, open)
+	})
+
+	t.Run("ok/customStopSignal", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cmd := exec.Command("sh", "-c", `trap 'exit 0' INT; while :; do sleep 0.01; done`)
+		p := gracefully.NewProcess(cmd, gracefully.WithStopSignal(syscall.SIGINT))
+		assert.NoError(t, p.Start(context.Background()))
+		time.Sleep(50 * time.Millisecond) // let the shell install the trap
+
+		// act
+		err := p.GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, cmd.ProcessState.Success())
+	})
+
+	t.Run("err/reportsExitStatus", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		p := gracefully.NewProcess(exec.Command("sh", "-c", "exit 3"))
+		assert.NoError(t, p.Start(context.Background()))
+		_ = p.Wait()
+
+		// act
+		err := p.GracefulShutdown(context.Background())
+
+		// assert
+		var exitErr *exec.ExitError
+		assert.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 3, exitErr.ExitCode())
+	})
+
+	t.Run("err/killsAfterGracePeriod", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cmd := exec.Command("sh", "-c", `trap '' TERM; sleep 10 & wait`)
+		p := gracefully.NewProcess(cmd, gracefully.WithGracePeriod(50*time.Millisecond))
+		assert.NoError(t, p.Start(context.Background()))
+		time.Sleep(50 * time.Millisecond) // let the shell install the trap
+
+		// act
+		start := time.Now()
+		err := p.GracefulShutdown(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrProcessKilled)
+		assert.ErrorContains(t, err, "signal: killed")
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("err/killsOnContextDone", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cmd := exec.Command("sh", "-c", `trap '' TERM; while :; do sleep 0.01; done`)
+		p := gracefully.NewProcess(cmd)
+		assert.NoError(t, p.Start(context.Background()))
+		time.Sleep(50 * time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := p.GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrProcessKilled)
+	})
+
+	t.Run("ok/notStarted", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		p := gracefully.NewProcess(exec.Command("sleep", "10"))
+
+		// act
+		err := p.GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("err/startTwice", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		p := gracefully.NewProcess(exec.Command("sleep", "10"))
+		assert.NoError(t, p.Start(context.Background()))
+		defer p.GracefulShutdown(context.Background())
+
+		// act
+		err := p.Start(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrStartCalled)
+	})
+
+	t.Run("err/startFails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		p := gracefully.NewProcess(exec.Command("/nonexistent/binary"))
+
+		// act
+		err := p.Start(context.Background())
+
+		// assert
+		assert.Error(t, err)
+		assert.NoError(t, p.GracefulShutdown(context.Background()))
+	})
+}
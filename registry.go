@@ -2,9 +2,13 @@ package gracefully
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"reflect"
+	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/lif0/pkg/utils/errx"
@@ -13,15 +17,48 @@ import (
 
 type anchor struct{ _ byte } // 1 byte size
 
+// hook is a registered callback together with a human-readable name.
+type hook struct {
+	name  string
+	f     func(context.Context) error
+	start func(context.Context) error // set for lifecycle components, see Start
+	child bool                        // a registry registered in another one, see NewChild
+	retry *retryConfig                // set for hooks registered with Retry
+}
+
+// runningHook is the hook Shutdown is currently waiting for.
+type runningHook struct {
+	name  string
+	since time.Time
+}
+
 // Registry is a thread-safe registry for instances which should be can graceful shutdown.
 //
 // Use NewRegister to create a new instance.
 type Registry struct {
-	mu sync.Mutex
+	mu  sync.Mutex
+	cfg registryConfig
 
-	gsiHash     *structx.OrderedMap[unsafe.Pointer, func(context.Context) error]
+	gsiHash     *structx.OrderedMap[unsafe.Pointer, hook]
 	gsiFuncAnch []*anchor // wee should save pointer, because GC can remove it
 
+	// reload hooks, ordered independently of the shutdown hooks
+	rlHash *structx.OrderedMap[unsafe.Pointer, hook]
+
+	// lifecycle components that failed to start, were rolled back or never reached
+	// by a failed Start; Shutdown skips them. nil until Start is called.
+	notStarted map[unsafe.Pointer]struct{}
+
+	// the hook Shutdown is currently waiting for, see RunningHook
+	running atomic.Pointer[runningHook]
+
+	// the last completed Shutdown, see Report
+	report atomic.Pointer[ShutdownReport]
+
+	// set for child registries, see NewChild
+	name   string
+	parent *Registry
+
 	// chan shutdown done
 	chsd     chan struct{}
 	disposed atomic.Bool
@@ -31,18 +68,36 @@ type Registry struct {
 //
 // If you want to set new Registerer as Global, use gogracefully.SetGlobal()
 // (e.g. for testing purposes).
-func NewRegistry() *Registry {
+func NewRegistry(opts ...RegistryOption) *Registry {
+	c := newDefaultRegistryConfig()
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	return &Registry{
-		mu: sync.Mutex{},
+		mu:  sync.Mutex{},
+		cfg: *c,
 
-		gsiHash:     structx.NewOrderedMap[unsafe.Pointer, func(context.Context) error](),
+		gsiHash:     structx.NewOrderedMap[unsafe.Pointer, hook](),
 		gsiFuncAnch: make([]*anchor, 0),
 
+		rlHash: structx.NewOrderedMap[unsafe.Pointer, hook](),
+
 		chsd:     make(chan struct{}),
 		disposed: atomic.Bool{},
 	}
 }
 
+// Configure applies opts to the registry; they take effect on the next Shutdown.
+func (r *Registry) Configure(opts ...RegistryOption) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, opt := range opts {
+		opt(&r.cfg)
+	}
+}
+
 // Register implements Registerer.
 func (r *Registry) Register(igs GracefulShutdownObject) error {
 	if err := r.isDisposed(); err != nil {
@@ -56,12 +111,26 @@ func (r *Registry) Register(igs GracefulShutdownObject) error {
 		return err
 	}
 
-	ptr := reflect.ValueOf(igs).UnsafePointer()
+	ptr := objectKey(igs)
 	if _, ok := r.gsiHash.Get(ptr); ok {
 		return ErrAlreadyRegistered
 	}
 
-	r.gsiHash.Put(ptr, igs.GracefulShutdown)
+	h := hook{name: objectName(igs), f: igs.GracefulShutdown}
+	if rh, ok := igs.(*retryHook); ok {
+		// Shutdown applies the policy itself, to report each attempt
+		h.f, h.retry = rh.obj.GracefulShutdown, rh.cfg
+		igs = rh.obj
+	}
+	switch v := igs.(type) {
+	case *Registry:
+		h.start = v.startNested
+		h.child = true
+	case Starter:
+		h.start = v.Start
+	}
+
+	r.gsiHash.Put(ptr, h)
 	return nil
 }
 
@@ -81,7 +150,7 @@ func (r *Registry) RegisterFunc(f func(context.Context) error) error {
 	anchor := &anchor{}
 	ptr := unsafe.Pointer(anchor)
 
-	r.gsiHash.Put(ptr, f)
+	r.gsiHash.Put(ptr, hook{name: funcName(f), f: f})
 	r.gsiFuncAnch = append(r.gsiFuncAnch, anchor)
 
 	return nil
@@ -100,7 +169,7 @@ func (r *Registry) Unregister(igs GracefulShutdownObject) bool {
 		return false
 	}
 
-	ptr := reflect.ValueOf(igs).UnsafePointer()
+	ptr := objectKey(igs)
 	if _, ok := r.gsiHash.Get(ptr); ok {
 		structx.Delete(r.gsiHash, ptr)
 		return true
@@ -120,6 +189,149 @@ func (r *Registry) MustRegister(igss ...GracefulShutdownObject) {
 	}
 }
 
+// RegisterReloader registers a Reloader to be invoked by Reload.
+//
+// Reloaders are kept in a list separate from the shutdown hooks and are
+// executed in the exact order they were registered.
+func (r *Registry) RegisterReloader(rl Reloader) error {
+	if err := r.isDisposed(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.isDisposed(); err != nil {
+		return err
+	}
+
+	ptr := reflect.ValueOf(rl).UnsafePointer()
+	if _, ok := r.rlHash.Get(ptr); ok {
+		return ErrAlreadyRegistered
+	}
+
+	r.rlHash.Put(ptr, hook{name: objectName(rl), f: rl.Reload})
+	return nil
+}
+
+// UnregisterReloader removes a Reloader previously added with RegisterReloader.
+// It reports whether the Reloader was registered.
+func (r *Registry) UnregisterReloader(rl Reloader) bool {
+	if r.isDisposed() != nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ptr := reflect.ValueOf(rl).UnsafePointer()
+	if _, ok := r.rlHash.Get(ptr); ok {
+		structx.Delete(r.rlHash, ptr)
+		return true
+	}
+
+	return false
+}
+
+// Reload invokes all registered Reloaders synchronously and in sequence.
+// Every Reloader is called even if a previous one failed; the errors are collected
+// into the returned MultiError. Reload is not available after Shutdown.
+// Reloaders registered while Reload is running are called on the next Reload.
+func (r *Registry) Reload(ctx context.Context) errx.MultiError {
+	if err := r.isDisposed(); err != nil {
+		return errx.MultiError{err}
+	}
+
+	r.mu.Lock()
+	if err := r.isDisposed(); err != nil {
+		r.mu.Unlock()
+		return errx.MultiError{err}
+	}
+	// run the Reloaders without the lock, so a slow one doesn't block
+	// the accessors (HookNames, ReloaderNames) or registrations made by a Reloader.
+	reloaders := make([]hook, 0)
+	for _, h := range r.rlHash.Iter() {
+		reloaders = append(reloaders, h)
+	}
+	r.mu.Unlock()
+
+	errs := errx.MultiError{}
+	for _, h := range reloaders {
+		if rlErr := h.f(ctx); rlErr != nil {
+			errs.Append(fmt.Errorf("%s: %w", h.name, rlErr))
+		}
+	}
+
+	return errs
+}
+
+// Start starts the registered lifecycle components (objects implementing Starter)
+// synchronously and in registration order. Child registries are started at their
+// position in the parent. Components registered while or after Start is running
+// (e.g. with NewChild) are not started, but are shut down like any other hook.
+//
+// If a component fails to start, the components that had already started are
+// shut down in reverse order with the same ctx, and Start returns the start error
+// followed by the errors of that rollback. A later Shutdown skips the component that
+// failed, the rolled back ones and the ones after it that were never started.
+//
+// Start can be called only once and not after Shutdown.
+func (r *Registry) Start(ctx context.Context) errx.MultiError {
+	if err := r.isDisposed(); err != nil {
+		return errx.MultiError{err}
+	}
+
+	r.mu.Lock()
+	if err := r.isDisposed(); err != nil {
+		r.mu.Unlock()
+		return errx.MultiError{err}
+	}
+	if r.notStarted != nil {
+		r.mu.Unlock()
+		return errx.MultiError{ErrStartCalled}
+	}
+	r.notStarted = make(map[unsafe.Pointer]struct{})
+
+	type component struct {
+		ptr unsafe.Pointer
+		h   hook
+	}
+	components := make([]component, 0)
+	for ptr, h := range r.gsiHash.Iter() {
+		if h.start != nil {
+			components = append(components, component{ptr: ptr, h: h})
+		}
+	}
+	// hooks run without the lock: a child registry unregisters itself from r on rollback.
+	r.mu.Unlock()
+
+	for i, c := range components {
+		if err := c.h.start(ctx); err != nil {
+			r.mu.Lock()
+			for _, c := range components {
+				r.notStarted[c.ptr] = struct{}{}
+			}
+			r.mu.Unlock()
+
+			errs := errx.MultiError{fmt.Errorf("%s: %w", c.h.name, err)}
+			for j := i - 1; j >= 0; j-- {
+				errs.Append(components[j].h.f(ctx))
+			}
+			return errs
+		}
+	}
+
+	return errx.MultiError{}
+}
+
+// startNested starts r as a lifecycle component of its parent.
+func (r *Registry) startNested(ctx context.Context) error {
+	if errs := r.Start(ctx); !errs.IsEmpty() {
+		return &ChildError{Name: r.name, Errs: errs}
+	}
+	return nil
+}
+
 // Shutdown implements Registerer.
 func (r *Registry) Shutdown(ctx context.Context) errx.MultiError {
 	if err := r.isDisposed(); err != nil {
@@ -127,28 +339,246 @@ func (r *Registry) Shutdown(ctx context.Context) errx.MultiError {
 	}
 
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if !r.disposed.CompareAndSwap(false, true) {
+		r.mu.Unlock()
 		return errx.MultiError{ErrShutdownCalled}
 	}
+	// disposed is already set, so the registry can't change anymore;
+	// release the lock to keep read-only accessors (HookNames) responsive.
+	hooks := make([]hook, 0)
+	for ptr, h := range r.gsiHash.Iter() {
+		if _, ok := r.notStarted[ptr]; ok {
+			continue // never started (or rolled back), nothing to shut down
+		}
+		hooks = append(hooks, h)
+	}
+	chsd := r.chsd
+	cfg := r.cfg
+	r.mu.Unlock()
+
+	if r.parent != nil {
+		// shut down on its own, the parent must not shut it down again
+		r.parent.Unregister(r)
+	}
 
+	report := &ShutdownReport{Started: time.Now(), Hooks: make([]HookReport, 0, len(hooks))}
 	errs := errx.MultiError{}
-	for _, f := range r.gsiHash.Iter() {
-		gsErr := f(ctx)
-		if gsErr != nil {
-			errs.Append(gsErr)
+	for _, h := range hooks {
+		hr := r.runHook(ctx, h, &cfg)
+		if hr.Err != nil {
+			errs.Append(hr.Err)
 		}
+		report.Hooks = append(report.Hooks, hr)
+	}
+	r.running.Store(nil)
+	report.Duration = time.Since(report.Started)
+	r.report.Store(report)
+
+	if rec := hookRecordFrom(ctx); rec != nil {
+		// r runs as a hook of a parent registry (see NewChild)
+		rec.spilled.Add(int64(report.Spilled()))
 	}
 
 	// broadcast for all who call WaitShutdown()
-	close(r.chsd)
+	close(chsd)
 	return errs
 }
 
+// runHook runs the shutdown hook h and describes how it went.
+func (r *Registry) runHook(ctx context.Context, h hook, cfg *registryConfig) HookReport {
+	since := time.Now()
+	r.running.Store(&runningHook{name: h.name, since: since})
+
+	if cfg.hardDeadline && ctx.Err() != nil {
+		// the deadline has passed, but the hook still gets its grace period
+		gctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.hardGrace)
+		defer cancel()
+		ctx = gctx
+	}
+
+	rec := &hookRecord{}
+	stop := func() ([]byte, string) { return nil, "" }
+	if !h.child { // a child registry watches its own hooks
+		stop = watchHung(ctx, h.name, cfg)
+	}
+
+	abandoned, attempts := retry(withHookRecord(ctx, rec), h.name, h.retry, func(ctx context.Context) (bool, error) {
+		if cfg.hardDeadline {
+			return runAbandonable(ctx, h.f)
+		}
+		return false, h.f(ctx)
+	})
+	err := attempts[len(attempts)-1]
+	stacks, file := stop()
+
+	if abandoned {
+		log.Printf("gogracefully: Abandoned hook %s\n", h.name)
+		err = fmt.Errorf("%w: %s: %w", ErrHookAbandoned, h.name, err)
+	}
+	if err != nil && stacks != nil {
+		err = &HungHookError{Name: h.name, Stacks: stacks, File: file, Err: err}
+	}
+	return HookReport{
+		Name:       h.name,
+		Duration:   time.Since(since),
+		Err:        err,
+		Spilled:    int(rec.spilled.Load()),
+		Abandoned:  abandoned,
+		Attempts:   attempts,
+		Stacks:     stacks,
+		StacksFile: file,
+	}
+}
+
+// runAbandonable runs f in its own goroutine and waits until it returns or ctx is done.
+// In the latter case it returns abandoned == true and ctx.Err(); f keeps running.
+func runAbandonable(ctx context.Context, f func(context.Context) error) (abandoned bool, err error) {
+	done := make(chan error, 1)
+	go func() { done <- f(ctx) }()
+
+	select {
+	case err := <-done:
+		return false, err
+	case <-ctx.Done():
+	}
+
+	select {
+	case err := <-done: // returned right at the deadline
+		return false, err
+	default:
+		return true, ctx.Err()
+	}
+}
+
+// NewChild creates a sub-registry registered as a single hook in r.
+//
+// Each subsystem (HTTP, consumers, storage, ...) can manage its own hooks and their
+// order in a child, while r orders the subsystems. When r shuts down, the child
+// shuts down all its hooks at its position in r; its errors are reported as a
+// *ChildError carrying name. A child shut down on its own is removed from r.
+//
+// The child starts with the options of r (see Configure).
+// A child that has been Reset is not attached to r again.
+func (r *Registry) NewChild(name string) (*Registry, error) {
+	child := NewRegistry()
+	child.name = name
+	r.mu.Lock()
+	child.cfg = r.cfg
+	r.mu.Unlock()
+	child.parent = r
+
+	if err := r.Register(child); err != nil {
+		return nil, err
+	}
+
+	return child, nil
+}
+
+// Name returns the name of a child registry, or an empty string for a root registry.
+func (r *Registry) Name() string {
+	return r.name
+}
+
+// GracefulShutdown implements GracefulShutdownObject, so a Registry can be
+// registered in another one (see NewChild). It shuts down r and reports its
+// errors as a *ChildError.
+func (r *Registry) GracefulShutdown(ctx context.Context) error {
+	if errs := r.Shutdown(ctx); !errs.IsEmpty() {
+		return &ChildError{Name: r.name, Errs: errs}
+	}
+	return nil
+}
+
 // WaitShutdown implements Registerer.
 func (r *Registry) WaitShutdown() {
-	<-r.chsd
+	<-r.done()
+}
+
+// Reset reopens the registry after Shutdown has completed, so it can be used again
+// (e.g. between tests or for an in-process restart). By default all registrations
+// are dropped; pass WithKeepRegistrations to keep them.
+//
+// If Shutdown is still running, Reset waits for it to complete. It returns
+// ErrNotShutdown if Shutdown was never called.
+//
+// Register calls racing with Reset either fail with ErrShutdownCalled (if they
+// observed the registry before it was reopened) or are registered in the reopened
+// registry; a registration is never silently lost.
+func (r *Registry) Reset(opts ...ResetOption) error {
+	c := &resetConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if r.isDisposed() == nil {
+		return ErrNotShutdown
+	}
+	<-r.done() // wait for running hooks
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.isDisposed() == nil { // reopened by a concurrent Reset
+		return ErrNotShutdown
+	}
+
+	if !c.keepRegistrations {
+		r.gsiHash = structx.NewOrderedMap[unsafe.Pointer, hook]()
+		r.gsiFuncAnch = make([]*anchor, 0)
+		r.rlHash = structx.NewOrderedMap[unsafe.Pointer, hook]()
+	}
+	r.notStarted = nil
+	r.chsd = make(chan struct{})
+
+	// must be the last step: it makes the registry available to Register again
+	r.disposed.Store(false)
+	return nil
+}
+
+// HookNames returns the names of the registered shutdown hooks in execution order.
+//
+// Objects are named after their dynamic type (e.g. "*main.eventBatcher"),
+// functions after their symbol name.
+func (r *Registry) HookNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return hookNames(r.gsiHash)
+}
+
+// RunningHook reports which hook Shutdown is currently waiting for and since when.
+// ok is false if Shutdown is not running a hook. It lets health checks detect
+// a hook that hangs.
+func (r *Registry) RunningHook() (name string, since time.Time, ok bool) {
+	if rh := r.running.Load(); rh != nil {
+		return rh.name, rh.since, true
+	}
+	return "", time.Time{}, false
+}
+
+// Report returns the report of the last completed Shutdown.
+// ok is false if no Shutdown has completed yet.
+func (r *Registry) Report() (report ShutdownReport, ok bool) {
+	if rp := r.report.Load(); rp != nil {
+		return *rp, true
+	}
+	return ShutdownReport{}, false
+}
+
+// ReloaderNames returns the names of the registered Reloaders in execution order.
+func (r *Registry) ReloaderNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return hookNames(r.rlHash)
+}
+
+// done returns the channel closed when the current Shutdown completes.
+func (r *Registry) done() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.chsd
 }
 
 // isDisposed ...
@@ -158,3 +588,37 @@ func (r *Registry) isDisposed() error {
 	}
 	return nil
 }
+
+func hookNames(m *structx.OrderedMap[unsafe.Pointer, hook]) []string {
+	names := make([]string, 0)
+	for _, h := range m.Iter() {
+		names = append(names, h.name)
+	}
+	return names
+}
+
+// objectName returns the dynamic type name of v, the name of a child registry,
+// or the name of an adapter (see FromFunc).
+func objectName(v any) string {
+	switch o := v.(type) {
+	case *Registry:
+		if o.name != "" {
+			return o.name
+		}
+	case *adapter:
+		return o.name
+	case *checkpoint:
+		return "checkpoint " + o.name
+	case *retryHook:
+		return objectName(o.obj)
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// funcName returns the symbol name of f, or "func" if it cannot be resolved.
+func funcName(f any) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "func"
+}
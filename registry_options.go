@@ -0,0 +1,68 @@
+package gracefully
+
+import "time"
+
+// registryConfig represents the configuration for a Registry.
+type registryConfig struct {
+	diagFraction float64
+	diagDir      string
+
+	hardDeadline bool
+	hardGrace    time.Duration
+}
+
+type RegistryOption func(*registryConfig)
+
+// WithTimeoutDiagnostics makes Shutdown capture the stacks of all goroutines
+// (pprof goroutine profile) when a hook is still running after fraction of the
+// time left until the shutdown deadline when the hook started: 1 captures them
+// at the deadline, 0.8 a bit earlier. It has no effect without a deadline
+// (see WithTimeout), nor on hooks started after the deadline.
+// A non-positive fraction disables it. Default: off.
+//
+// The stacks are written to the log (or to a file, see WithDiagnosticsDir) as soon
+// as they are captured, since a hook that never returns produces no report. They are
+// also attached to the hook's HookReport and, if the hook fails, to its error as
+// a *HungHookError.
+//
+// Example:
+//
+//	gracefully.Configure(gracefully.WithTimeoutDiagnostics(0.9), gracefully.WithDiagnosticsDir("/var/log/app"))
+func WithTimeoutDiagnostics(fraction float64) RegistryOption {
+	return func(c *registryConfig) {
+		c.diagFraction = fraction
+	}
+}
+
+// WithDiagnosticsDir makes the stacks captured by WithTimeoutDiagnostics be written
+// to a file in dir, for post-mortem debugging, instead of the log.
+// dir is created if needed.
+func WithDiagnosticsDir(dir string) RegistryOption {
+	return func(c *registryConfig) {
+		c.diagDir = dir
+	}
+}
+
+// WithHardDeadline enforces the shutdown deadline: each hook runs in its own goroutine
+// and is abandoned once the shutdown context is done, so a hook that ignores its
+// context can't block Shutdown (and WaitShutdown) forever. The hooks that didn't
+// run before the deadline still run, each with its own context that expires
+// after grace, and are abandoned when it does.
+//
+// Abandoned hooks are reported with ErrHookAbandoned and HookReport.Abandoned;
+// they keep running in the background until they return. Default: off.
+//
+// Example:
+//
+//	gracefully.Configure(gracefully.WithHardDeadline(time.Second))
+func WithHardDeadline(grace time.Duration) RegistryOption {
+	return func(c *registryConfig) {
+		c.hardDeadline = true
+		c.hardGrace = grace
+	}
+}
+
+// newDefaultRegistryConfig create default config
+func newDefaultRegistryConfig() *registryConfig {
+	return &registryConfig{}
+}
@@ -403,3 +403,361 @@ func Test_WaitShutdown(t *testing.T) {
 		}
 	})
 }
+
+func Test_Reset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/dropsRegistrations", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		a := &stubGSO{}
+		assert.NoError(t, r.Register(a))
+		_ = r.Shutdown(context.Background())
+
+		// act
+		err := r.Reset()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, r.HookNames())
+		assert.NoError(t, r.Register(a))
+		assert.True(t, r.Shutdown(context.Background()).IsEmpty())
+		assert.Equal(t, int32(2), atomic.LoadInt32(&a.calls))
+	})
+
+	t.Run("ok/keepsRegistrations", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		a := &stubGSO{}
+		assert.NoError(t, r.Register(a))
+		_ = r.Shutdown(context.Background())
+
+		// act
+		err := r.Reset(gracefully.WithKeepRegistrations())
+
+		// assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, r.Register(a), gracefully.ErrAlreadyRegistered)
+		_ = r.Shutdown(context.Background())
+		assert.Equal(t, int32(2), atomic.LoadInt32(&a.calls))
+	})
+
+	t.Run("ok/freshDoneChannel", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		_ = r.Shutdown(context.Background())
+		assert.NoError(t, r.Reset())
+		done := make(chan struct{})
+
+		// act
+		go func() {
+			r.WaitShutdown()
+			close(done)
+		}()
+
+		// assert
+		select {
+		case <-done:
+			t.Fatalf("WaitShutdown must block after Reset")
+		case <-time.After(80 * time.Millisecond):
+		}
+		_ = r.Shutdown(context.Background())
+		select {
+		case <-done:
+		case <-time.After(150 * time.Millisecond):
+			t.Fatalf("WaitShutdown did not unblock after Shutdown")
+		}
+	})
+
+	t.Run("ok/waitsForRunningShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		release := make(chan struct{})
+		assert.NoError(t, r.RegisterFunc(func(context.Context) error {
+			<-release
+			return nil
+		}))
+		go r.Shutdown(context.Background())
+		assert.Eventually(t, func() bool {
+			return errors.Is(r.Register(&stubGSO{}), gracefully.ErrShutdownCalled)
+		}, time.Second, time.Millisecond)
+		reset := make(chan error)
+
+		// act
+		go func() { reset <- r.Reset() }()
+
+		// assert
+		select {
+		case <-reset:
+			t.Fatalf("Reset must wait for running hooks")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+		assert.NoError(t, <-reset)
+	})
+
+	t.Run("err/notShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+
+		// act
+		err := r.Reset()
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrNotShutdown)
+	})
+
+	t.Run("race/concurrentRegister", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		_ = r.Shutdown(context.Background())
+		const n = 32
+		objs := make([]*stubGSO, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		wg.Add(n)
+
+		// act
+		for i := 0; i < n; i++ {
+			objs[i] = &stubGSO{}
+			go func() {
+				defer wg.Done()
+				errs[i] = r.Register(objs[i])
+			}()
+		}
+		assert.NoError(t, r.Reset())
+		wg.Wait()
+
+		// assert: every object is either rejected or registered in the reopened registry
+		_ = r.Shutdown(context.Background())
+		for i := 0; i < n; i++ {
+			if errs[i] != nil {
+				assert.ErrorIs(t, errs[i], gracefully.ErrShutdownCalled)
+				assert.Equal(t, int32(0), atomic.LoadInt32(&objs[i].calls))
+				continue
+			}
+			assert.Equal(t, int32(1), atomic.LoadInt32(&objs[i].calls))
+		}
+	})
+}
+
+func Test_NewChild(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/shutsDownInParentOrder", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var order []string
+		record := func(name string) func(context.Context) error {
+			return func(context.Context) error {
+				order = append(order, name)
+				return nil
+			}
+		}
+		r := gracefully.NewRegistry()
+		http, err := r.NewChild("http")
+		assert.NoError(t, err)
+		assert.NoError(t, r.RegisterFunc(record("root")))
+		storage, err := r.NewChild("storage")
+		assert.NoError(t, err)
+		assert.NoError(t, storage.RegisterFunc(record("storage/db")))
+		assert.NoError(t, http.RegisterFunc(record("http/server")))
+		assert.NoError(t, http.RegisterFunc(record("http/clients")))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		assert.Equal(t, []string{"http/server", "http/clients", "root", "storage/db"}, order)
+		assert.Equal(t, "http", http.Name())
+		assert.Equal(t, "http", r.HookNames()[0])
+		assert.Equal(t, "storage", r.HookNames()[2])
+	})
+
+	t.Run("ok/errorsNestedUnderName", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("boom")
+		r := gracefully.NewRegistry()
+		child, err := r.NewChild("consumers")
+		assert.NoError(t, err)
+		assert.NoError(t, child.Register(&stubGSO{ret: boom}))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.Len(t, me, 1)
+		var ce *gracefully.ChildError
+		assert.ErrorAs(t, me[0], &ce)
+		assert.Equal(t, "consumers", ce.Name)
+		assert.ErrorIs(t, me[0], boom)
+		assert.Contains(t, me[0].Error(), "consumers: 1 error(s) occurred")
+	})
+
+	t.Run("ok/independentShutdownRemovesFromParent", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		child, err := r.NewChild("http")
+		assert.NoError(t, err)
+		a := &stubGSO{}
+		assert.NoError(t, child.Register(a))
+
+		// act
+		childErrs := child.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, childErrs.IsEmpty())
+		assert.Empty(t, r.HookNames())
+		assert.True(t, r.Shutdown(context.Background()).IsEmpty())
+		assert.Equal(t, int32(1), atomic.LoadInt32(&a.calls))
+	})
+
+	t.Run("err/parentShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		_ = r.Shutdown(context.Background())
+
+		// act
+		child, err := r.NewChild("late")
+
+		// assert
+		assert.Nil(t, child)
+		assert.ErrorIs(t, err, gracefully.ErrShutdownCalled)
+	})
+}
+
+func Test_Report(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/describesHooks", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("boom")
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.FromFunc("ok", func(context.Context) error { return nil }))
+		r.MustRegister(gracefully.FromFunc("failing", func(context.Context) error { return boom }))
+
+		// act
+		r.Shutdown(context.Background())
+
+		// assert
+		report, ok := r.Report()
+		assert.True(t, ok)
+		assert.False(t, report.Started.IsZero())
+		assert.Len(t, report.Hooks, 2)
+		assert.Equal(t, "ok", report.Hooks[0].Name)
+		assert.NoError(t, report.Hooks[0].Err)
+		assert.Equal(t, "failing", report.Hooks[1].Name)
+		assert.ErrorIs(t, report.Hooks[1].Err, boom)
+		assert.Zero(t, report.Spilled())
+	})
+
+	t.Run("edge/beforeShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+
+		// act
+		_, ok := r.Report()
+
+		// assert
+		assert.False(t, ok)
+	})
+}
+
+// hungHook is a hook that ignores its context until released.
+type hungHook struct{ release <-chan struct{} }
+
+func (h *hungHook) GracefulShutdown(context.Context) error {
+	<-h.release
+	return nil
+}
+
+func Test_WithHardDeadline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/abandonsHungHookAndRunsTheRest", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		release := make(chan struct{})
+		defer close(release)
+		r := gracefully.NewRegistry(gracefully.WithHardDeadline(time.Second))
+		r.MustRegister(&hungHook{release: release})
+		var lateCtxErr error
+		assert.NoError(t, r.RegisterFunc(func(ctx context.Context) error {
+			lateCtxErr = ctx.Err()
+			return nil
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		start := time.Now()
+		me := r.Shutdown(ctx)
+
+		// assert
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Len(t, me, 1)
+		assert.ErrorIs(t, me[0], gracefully.ErrHookAbandoned)
+		assert.ErrorIs(t, me[0], context.DeadlineExceeded)
+		assert.ErrorContains(t, me[0], "hungHook")
+		assert.NoError(t, lateCtxErr, "the remaining hook gets its grace period")
+		report, _ := r.Report()
+		assert.Equal(t, []string{"*gracefully_test.hungHook"}, report.Abandoned())
+		assert.False(t, report.Hooks[1].Abandoned)
+	})
+
+	t.Run("err/abandonsAfterGrace", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		release := make(chan struct{})
+		defer close(release)
+		r := gracefully.NewRegistry(gracefully.WithHardDeadline(30 * time.Millisecond))
+		r.MustRegister(&hungHook{release: release}, &hungHook{release: release})
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		// act
+		done := make(chan struct{})
+		go func() {
+			r.Shutdown(ctx)
+			close(done)
+		}()
+
+		// assert
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Shutdown is blocked by hung hooks")
+		}
+		r.WaitShutdown()
+		report, _ := r.Report()
+		assert.Len(t, report.Abandoned(), 2)
+	})
+
+	t.Run("ok/returnsResultBeforeDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("boom")
+		r := gracefully.NewRegistry(gracefully.WithHardDeadline(0))
+		assert.NoError(t, r.RegisterFunc(func(context.Context) error { return boom }))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.Equal(t, errx.MultiError{boom}, me)
+		report, _ := r.Report()
+		assert.Empty(t, report.Abandoned())
+	})
+}
@@ -0,0 +1,153 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubReloader struct {
+	calls int
+	ret   error
+	order *[]string
+	name  string
+}
+
+func (s *stubReloader) Reload(context.Context) error {
+	s.calls++
+	if s.order != nil {
+		*s.order = append(*s.order, s.name)
+	}
+	return s.ret
+}
+
+func Test_Reload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/callsInOrder_and_collectsErrors", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		var order []string
+		a := &stubReloader{order: &order, name: "a"}
+		b := &stubReloader{order: &order, name: "b", ret: errors.New("bad config")}
+		c := &stubReloader{order: &order, name: "c"}
+		assert.NoError(t, r.RegisterReloader(a))
+		assert.NoError(t, r.RegisterReloader(b))
+		assert.NoError(t, r.RegisterReloader(c))
+
+		// act
+		me := r.Reload(context.Background())
+
+		// assert
+		assert.Equal(t, []string{"a", "b", "c"}, order)
+		assertMultiErrorContains(t, me, b.ret)
+		assert.Len(t, me, 1)
+	})
+
+	t.Run("ok/separateFromShutdownHooks", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		rl := &stubReloader{}
+		gso := &stubGSO{}
+		assert.NoError(t, r.RegisterReloader(rl))
+		assert.NoError(t, r.Register(gso))
+
+		// act
+		me := r.Reload(context.Background())
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		assert.Equal(t, 1, rl.calls)
+		assert.Equal(t, int32(0), gso.calls)
+		assert.Equal(t, []string{"*gracefully_test.stubReloader"}, r.ReloaderNames())
+		assert.Equal(t, []string{"*gracefully_test.stubGSO"}, r.HookNames())
+	})
+
+	t.Run("err/duplicate", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		rl := &stubReloader{}
+		assert.NoError(t, r.RegisterReloader(rl))
+
+		// act
+		err := r.RegisterReloader(rl)
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrAlreadyRegistered)
+		assert.True(t, r.UnregisterReloader(rl))
+		assert.False(t, r.UnregisterReloader(rl))
+	})
+
+	t.Run("err/afterShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		_ = r.Shutdown(context.Background())
+
+		// act
+		me := r.Reload(context.Background())
+
+		// assert
+		assertMultiErrorContains(t, me, gracefully.ErrShutdownCalled)
+		assert.ErrorIs(t, r.RegisterReloader(&stubReloader{}), gracefully.ErrShutdownCalled)
+	})
+
+	t.Run("ok/slowReloaderDoesNotBlockRegistry", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		release := make(chan struct{})
+		entered := make(chan struct{})
+		assert.NoError(t, r.RegisterReloader(reloaderFunc(func(context.Context) error {
+			close(entered)
+			<-release
+			return nil
+		})))
+		done := make(chan struct{})
+		go func() {
+			r.Reload(context.Background())
+			close(done)
+		}()
+		<-entered
+
+		// act
+		names := r.ReloaderNames()
+		hooks := r.HookNames()
+		close(release)
+
+		// assert
+		assert.Len(t, names, 1)
+		assert.Empty(t, hooks)
+		<-done
+	})
+
+	t.Run("ok/reloaderRegisters", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		late := &stubReloader{}
+		assert.NoError(t, r.RegisterReloader(reloaderFunc(func(context.Context) error {
+			return r.RegisterReloader(late)
+		})))
+
+		// act
+		me := r.Reload(context.Background())
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		assert.Zero(t, late.calls, "called on the next Reload")
+		r.Reload(context.Background())
+		assert.Equal(t, 1, late.calls)
+	})
+}
+
+// reloaderFunc adapts a function to Reloader.
+type reloaderFunc func(context.Context) error
+
+func (f reloaderFunc) Reload(ctx context.Context) error { return f(ctx) }
@@ -0,0 +1,70 @@
+package gracefully
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ShutdownReport describes a completed Registry.Shutdown, see Registry.Report.
+type ShutdownReport struct {
+	Started  time.Time
+	Duration time.Duration
+	Hooks    []HookReport // in execution order
+}
+
+// HookReport describes a hook run by Registry.Shutdown.
+type HookReport struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+	Spilled  int // records the hook spilled with Spool.Spill
+
+	// the error of each call, in order; more than one for hooks registered with Retry
+	Attempts []error
+
+	// the hook was still running when its context was done, see WithHardDeadline
+	Abandoned bool
+
+	// goroutine stacks captured while the hook was running, see WithTimeoutDiagnostics
+	Stacks     []byte
+	StacksFile string
+}
+
+// Abandoned returns the names of the abandoned hooks.
+func (r ShutdownReport) Abandoned() []string {
+	names := make([]string, 0)
+	for _, h := range r.Hooks {
+		if h.Abandoned {
+			names = append(names, h.Name)
+		}
+	}
+	return names
+}
+
+// Spilled returns the number of records spilled by all hooks.
+func (r ShutdownReport) Spilled() int {
+	n := 0
+	for _, h := range r.Hooks {
+		n += h.Spilled
+	}
+	return n
+}
+
+// hookRecord collects what a running hook reports through its context.
+type hookRecord struct {
+	spilled atomic.Int64
+}
+
+type hookRecordKey struct{}
+
+// withHookRecord returns ctx carrying rec, so helpers called by the hook can report to it.
+func withHookRecord(ctx context.Context, rec *hookRecord) context.Context {
+	return context.WithValue(ctx, hookRecordKey{}, rec)
+}
+
+// hookRecordFrom returns the record of the hook ctx was passed to, or nil.
+func hookRecordFrom(ctx context.Context) *hookRecord {
+	rec, _ := ctx.Value(hookRecordKey{}).(*hookRecord)
+	return rec
+}
@@ -0,0 +1,16 @@
+package gracefully
+
+// resetConfig represents the configuration for Registry.Reset.
+type resetConfig struct {
+	keepRegistrations bool
+}
+
+type ResetOption func(*resetConfig)
+
+// WithKeepRegistrations keeps the registered hooks and Reloaders when the registry is reset,
+// so the same objects are shut down again on the next Shutdown.
+func WithKeepRegistrations() ResetOption {
+	return func(c *resetConfig) {
+		c.keepRegistrations = true
+	}
+}
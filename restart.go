@@ -0,0 +1,201 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Environment variables passed to the new copy of the process by ActionRestart.
+const (
+	// envListeners describes the inherited listeners as comma-separated
+	// "network:address" entries; the i-th listener is file descriptor 3+i.
+	envListeners = "GRACEFULLY_LISTENERS"
+	// envReadyFD is the file descriptor of the pipe NotifyReady writes to.
+	envReadyFD = "GRACEFULLY_READY_FD"
+)
+
+// restarting is set while ActionRestart is running.
+var restarting atomic.Bool
+
+// selfCommand returns the command that starts a new copy of the process, replaced in tests.
+var selfCommand = func() (*exec.Cmd, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(exe, os.Args[1:]...), nil
+}
+
+var inherited struct {
+	once      sync.Once
+	listeners []net.Listener
+	err       error
+
+	readyOnce sync.Once
+	readyErr  error
+}
+
+// InheritedListeners returns the listeners passed by the process that restarted
+// this one (see WithRestart), in the order they were given to WithRestart.
+// It returns nil if the process was not started by a restart.
+//
+// The listeners are created once; later calls return the same values.
+//
+// Example:
+//
+//	lns, err := gracefully.InheritedListeners()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	var ln net.Listener
+//	if len(lns) > 0 {
+//		ln = lns[0]
+//	} else if ln, err = net.Listen("tcp", ":8080"); err != nil {
+//		log.Fatal(err)
+//	}
+func InheritedListeners() ([]net.Listener, error) {
+	inherited.once.Do(func() {
+		inherited.listeners, inherited.err = inheritListeners(os.Getenv(envListeners))
+	})
+	return inherited.listeners, inherited.err
+}
+
+// NotifyReady tells the process that restarted this one (see WithRestart) that
+// it is ready to serve, so the old process starts its graceful shutdown.
+// It is a no-op if the process was not started by a restart, and when called again.
+func NotifyReady() error {
+	inherited.readyOnce.Do(func() {
+		v := os.Getenv(envReadyFD)
+		if v == "" {
+			return
+		}
+		fd, err := strconv.Atoi(v)
+		if err != nil {
+			inherited.readyErr = fmt.Errorf("%s: %w", envReadyFD, err)
+			return
+		}
+
+		f := os.NewFile(uintptr(fd), "ready")
+		_, err = f.Write([]byte{1})
+		inherited.readyErr = errors.Join(err, f.Close())
+	})
+	return inherited.readyErr
+}
+
+func inheritListeners(desc string) ([]net.Listener, error) {
+	if desc == "" {
+		return nil, nil
+	}
+
+	entries := strings.Split(desc, ",")
+	listeners := make([]net.Listener, 0, len(entries))
+	for i, entry := range entries {
+		f := os.NewFile(uintptr(3+i), entry)
+		ln, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, fmt.Errorf("inherited listener %s: %w", entry, err)
+		}
+		listeners = append(listeners, ln)
+	}
+	return listeners, nil
+}
+
+// restart starts a new copy of the process with the listeners of c and waits
+// until it calls NotifyReady. It returns the pid of the new process.
+func restart(ctx context.Context, c *triggerConfig) (int, error) {
+	files := make([]*os.File, 0, len(c.restartListeners)+1)
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	descs := make([]string, 0, len(c.restartListeners))
+	for _, ln := range c.restartListeners {
+		f, err := listenerFile(ln)
+		if err != nil {
+			return 0, fmt.Errorf("listener %s: %w", ln.Addr(), err)
+		}
+		files = append(files, f)
+		descs = append(descs, ln.Addr().Network()+":"+ln.Addr().String())
+	}
+
+	ready, readyw, err := os.Pipe()
+	if err != nil {
+		return 0, err
+	}
+	defer ready.Close()
+	files = append(files, readyw)
+
+	cmd, err := selfCommand()
+	if err != nil {
+		return 0, err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(restartEnv(env),
+		envListeners+"="+strings.Join(descs, ","),
+		envReadyFD+"="+strconv.Itoa(3+len(files)-1),
+	)
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	_ = readyw.Close() // so the read below fails when the new process exits
+
+	var deadline time.Time // zero: no deadline
+	if c.restartTimeout > 0 {
+		deadline = time.Now().Add(c.restartTimeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	_ = ready.SetReadDeadline(deadline)
+
+	if _, err := ready.Read(make([]byte, 1)); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return 0, fmt.Errorf("new process pid %d is not ready: %w", cmd.Process.Pid, err)
+	}
+	return cmd.Process.Pid, nil
+}
+
+// listenerFile returns a duplicate of the file descriptor of ln.
+func listenerFile(ln net.Listener) (*os.File, error) {
+	if tl, ok := ln.(*TrackingListener); ok {
+		ln = tl.Listener
+	}
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("%T has no file descriptor", ln)
+	}
+	return filer.File()
+}
+
+// restartEnv returns env without the variables set by a previous restart.
+func restartEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		if strings.HasPrefix(kv, envListeners+"=") || strings.HasPrefix(kv, envReadyFD+"=") {
+			continue
+		}
+		out = append(out, kv)
+	}
+	return out
+}
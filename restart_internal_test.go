@@ -0,0 +1,135 @@
+//go:build unix
+
+package gracefully
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// envRestartHelper makes Test_restartHelper act as the new copy of the process.
+const envRestartHelper = "GRACEFULLY_RESTART_HELPER"
+
+// Test_restartHelper is run by Test_restart in a new process: it serves one
+// connection on the inherited listener with the given reply.
+func Test_restartHelper(t *testing.T) {
+	mode := os.Getenv(envRestartHelper)
+	if mode == "" {
+		t.Skip("helper process")
+	}
+	if mode == "not-ready" {
+		time.Sleep(10 * time.Second)
+		return
+	}
+
+	lns, err := InheritedListeners()
+	if err != nil || len(lns) != 1 {
+		os.Exit(2)
+	}
+	if err := NotifyReady(); err != nil {
+		os.Exit(3)
+	}
+	conn, err := lns[0].Accept()
+	if err != nil {
+		os.Exit(4)
+	}
+	_, _ = conn.Write([]byte(mode))
+	_ = conn.Close()
+	os.Exit(0)
+}
+
+// useRestartHelper makes restart run Test_restartHelper in the given mode.
+func useRestartHelper(t *testing.T, mode string) {
+	old := selfCommand
+	selfCommand = func() (*exec.Cmd, error) {
+		cmd := exec.Command(os.Args[0], "-test.run=^Test_restartHelper$")
+		cmd.Env = append(os.Environ(), envRestartHelper+"="+mode)
+		return cmd, nil
+	}
+	t.Cleanup(func() { selfCommand = old })
+}
+
+func Test_restart(t *testing.T) {
+	t.Run("ok/handsOverListener", func(t *testing.T) {
+		// arrange
+		useRestartHelper(t, "child")
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer ln.Close()
+		c := newDefaultTriggerConfig()
+		WithRestart(os.Interrupt, Listener(ln))(c)
+
+		// act
+		pid, err := restart(context.Background(), c)
+
+		// assert
+		assert.NoError(t, err)
+		assert.NotZero(t, pid)
+		ln.Close() // the old process stops accepting, the new one keeps serving
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		reply, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, "child", string(reply))
+	})
+
+	t.Run("err/notReady", func(t *testing.T) {
+		// arrange
+		useRestartHelper(t, "not-ready")
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer ln.Close()
+		c := newDefaultTriggerConfig()
+		WithRestart(os.Interrupt, ln)(c)
+		WithRestartTimeout(200 * time.Millisecond)(c)
+
+		// act
+		_, err = restart(context.Background(), c)
+
+		// assert
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.ErrorContains(t, err, "is not ready")
+	})
+
+	t.Run("err/noFileDescriptor", func(t *testing.T) {
+		// arrange
+		c := newDefaultTriggerConfig()
+		WithRestart(os.Interrupt, fakeListener{})(c)
+
+		// act
+		_, err := restart(context.Background(), c)
+
+		// assert
+		assert.ErrorContains(t, err, "has no file descriptor")
+	})
+}
+
+func Test_restartEnv(t *testing.T) {
+	t.Parallel()
+
+	// act
+	env := restartEnv([]string{"A=1", envListeners + "=tcp:x", envReadyFD + "=4", "B=2"})
+
+	// assert
+	assert.Equal(t, []string{"A=1", "B=2"}, env)
+}
+
+func Test_InheritedListeners_notRestarted(t *testing.T) {
+	t.Parallel()
+
+	// act
+	lns, err := inheritListeners("")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Nil(t, lns)
+}
@@ -0,0 +1,90 @@
+package gracefully
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// retryHook is a GracefulShutdownObject with a retry policy, see Retry.
+type retryHook struct {
+	obj GracefulShutdownObject
+	cfg *retryConfig
+}
+
+// Retry returns obj with a retry policy: when its GracefulShutdown fails,
+// Registry.Shutdown calls it again after a backoff (see WithMaxAttempts,
+// WithBackoff, WithJitter, WithRetryIf), as long as the next attempt can start
+// before the shutdown deadline. The error of each attempt is recorded in
+// HookReport.Attempts; only the last one is reported by Shutdown.
+//
+// Registrations are identified by obj, so Unregister(obj) and Unregister(Retry(obj))
+// both work. If obj is a Starter, it is still started by Registry.Start.
+//
+// Example:
+//
+//	gracefully.MustRegister(gracefully.Retry(batcher, gracefully.WithMaxAttempts(5)))
+func Retry(obj GracefulShutdownObject, opts ...RetryOption) GracefulShutdownObject {
+	c := newDefaultRetryConfig()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return &retryHook{obj: obj, cfg: c}
+}
+
+// wrapped implements wrapper.
+func (rh *retryHook) wrapped() any {
+	if w, ok := rh.obj.(wrapper); ok && w.wrapped() != nil {
+		return w.wrapped()
+	}
+	return rh.obj
+}
+
+// GracefulShutdown implements GracefulShutdownObject. It applies the retry policy
+// when called directly, outside of Registry.Shutdown.
+func (rh *retryHook) GracefulShutdown(ctx context.Context) error {
+	_, errs := retry(ctx, objectName(rh.obj), rh.cfg, func(ctx context.Context) (bool, error) {
+		return false, rh.obj.GracefulShutdown(ctx)
+	})
+	return errs[len(errs)-1]
+}
+
+// retry calls attempt until it succeeds, is abandoned, or c doesn't allow another
+// attempt. It returns the error of each attempt; c may be nil for a single attempt.
+func retry(ctx context.Context, name string, c *retryConfig, attempt func(context.Context) (abandoned bool, err error)) (abandoned bool, errs []error) {
+	for n := 1; ; n++ {
+		abandoned, err := attempt(ctx)
+		errs = append(errs, err)
+		if err == nil || abandoned || c == nil || n >= c.maxAttempts || !c.retryIf(err) {
+			return abandoned, errs
+		}
+
+		delay := c.backoff(n)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return false, errs // the next attempt wouldn't start in time
+		}
+		log.Printf("gogracefully: Hook %s failed (attempt %d/%d) - %v, retrying in %s\n", name, n, c.maxAttempts, err, delay)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return false, errs
+		}
+	}
+}
+
+// backoff returns the delay after the n-th failed attempt.
+func (c *retryConfig) backoff(n int) time.Duration {
+	d := c.initial
+	for i := 1; i < n && d < c.max; i++ {
+		d *= 2
+	}
+	d = min(d, c.max)
+	if c.jitter > 0 {
+		d += rand.N(c.jitter)
+	}
+	return d
+}
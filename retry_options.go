@@ -0,0 +1,63 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// retryConfig represents the retry policy of a hook, see Retry.
+type retryConfig struct {
+	maxAttempts int
+	initial     time.Duration
+	max         time.Duration
+	jitter      time.Duration
+	retryIf     func(error) bool
+}
+
+type RetryOption func(*retryConfig)
+
+// WithMaxAttempts sets how many times the hook is called at most, including
+// the first call. Default: 3.
+func WithMaxAttempts(n int) RetryOption {
+	return func(c *retryConfig) {
+		c.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before the second attempt; each following delay is
+// doubled, up to max. Default: 100ms, up to 2s.
+func WithBackoff(initial, max time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.initial = initial
+		c.max = max
+	}
+}
+
+// WithJitter adds a random duration in [0, jitter) to each delay, so replicas
+// don't retry against a shared sink in lockstep. Default: 50ms.
+func WithJitter(jitter time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.jitter = jitter
+	}
+}
+
+// WithRetryIf sets the predicate deciding whether a failed attempt is retried.
+// By default every error is retried, except context.Canceled and context.DeadlineExceeded.
+func WithRetryIf(retryIf func(error) bool) RetryOption {
+	return func(c *retryConfig) {
+		c.retryIf = retryIf
+	}
+}
+
+// newDefaultRetryConfig create default config
+func newDefaultRetryConfig() *retryConfig {
+	c := &retryConfig{}
+	WithMaxAttempts(3)(c)
+	WithBackoff(100*time.Millisecond, 2*time.Second)(c)
+	WithJitter(50 * time.Millisecond)(c)
+	WithRetryIf(func(err error) bool {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	})(c)
+	return c
+}
@@ -0,0 +1,139 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/lif0/pkg/utils/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakySink fails the first failures calls of GracefulShutdown.
+type flakySink struct {
+	failures int
+	calls    int
+	err      error
+}
+
+func (s *flakySink) GracefulShutdown(context.Context) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return s.err
+	}
+	return nil
+}
+
+func Test_Retry(t *testing.T) {
+	t.Parallel()
+
+	fast := []gracefully.RetryOption{gracefully.WithBackoff(time.Millisecond, 5*time.Millisecond), gracefully.WithJitter(time.Millisecond)}
+
+	t.Run("ok/recoversFromTransientFailure", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("sink unavailable")
+		sink := &flakySink{failures: 2, err: boom}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Retry(sink, fast...))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		assert.Equal(t, 3, sink.calls)
+		report, _ := r.Report()
+		assert.Equal(t, []error{boom, boom, nil}, report.Hooks[0].Attempts)
+		assert.NoError(t, report.Hooks[0].Err)
+		assert.Equal(t, "*gracefully_test.flakySink", report.Hooks[0].Name)
+	})
+
+	t.Run("err/reportsOnlyFinalFailure", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("sink unavailable")
+		sink := &flakySink{failures: 10, err: boom}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Retry(sink, append(fast, gracefully.WithMaxAttempts(4))...))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.Equal(t, errx.MultiError{boom}, me)
+		assert.Equal(t, 4, sink.calls)
+		report, _ := r.Report()
+		assert.Len(t, report.Hooks[0].Attempts, 4)
+	})
+
+	t.Run("err/retryIfRejects", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fatal := errors.New("bad credentials")
+		sink := &flakySink{failures: 10, err: fatal}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Retry(sink, append(fast, gracefully.WithRetryIf(func(err error) bool {
+			return !errors.Is(err, fatal)
+		}))...))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.Equal(t, errx.MultiError{fatal}, me)
+		assert.Equal(t, 1, sink.calls)
+	})
+
+	t.Run("err/stopsBeforeDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("sink unavailable")
+		sink := &flakySink{failures: 10, err: boom}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Retry(sink, gracefully.WithMaxAttempts(100), gracefully.WithBackoff(20*time.Millisecond, 20*time.Millisecond)))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		me := r.Shutdown(ctx)
+
+		// assert: attempts at 0, 20 and 40ms at most, none after the deadline
+		assert.Equal(t, errx.MultiError{boom}, me)
+		report, _ := r.Report()
+		assert.Len(t, report.Hooks[0].Attempts, sink.calls)
+		assert.GreaterOrEqual(t, sink.calls, 1)
+		assert.LessOrEqual(t, sink.calls, 3)
+	})
+
+	t.Run("ok/unregisterByObject", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		sink := &flakySink{}
+		r := gracefully.NewRegistry()
+		r.MustRegister(gracefully.Retry(sink))
+
+		// act
+		dup := r.Register(sink)
+		ok := r.Unregister(sink)
+
+		// assert
+		assert.ErrorIs(t, dup, gracefully.ErrAlreadyRegistered)
+		assert.True(t, ok)
+	})
+
+	t.Run("ok/calledDirectly", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		sink := &flakySink{failures: 1, err: errors.New("once")}
+
+		// act
+		err := gracefully.Retry(sink, fast...).GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sink.calls)
+	})
+}
@@ -0,0 +1,123 @@
+package gracefully
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime/pprof"
+	"sync"
+
+	"github.com/lif0/pkg/utils/errx"
+)
+
+// SignalAction describes what the shutdown trigger does when a routed signal is received.
+type SignalAction byte
+
+const (
+	// ActionShutdown starts graceful shutdown, the same way SIGINT/SIGTERM do.
+	ActionShutdown SignalAction = iota
+
+	// ActionReload runs the registered Reloaders (see Registry.Reload).
+	// Reloads run one at a time; errors are collected into GlobalReloadError.
+	ActionReload
+
+	// ActionDump writes goroutine stacks and the registry contents to the dump writer.
+	ActionDump
+
+	// ActionForceExit terminates the process immediately without running any hooks.
+	ActionForceExit
+
+	// ActionRestart starts a new copy of the process that inherits the listeners,
+	// and shuts this one down once the copy is ready (see WithRestart).
+	ActionRestart
+)
+
+// String implements the Stringer interface.
+func (a SignalAction) String() string {
+	switch a {
+	case ActionShutdown:
+		return "Shutdown"
+	case ActionReload:
+		return "Reload"
+	case ActionDump:
+		return "Dump"
+	case ActionForceExit:
+		return "ForceExit"
+	case ActionRestart:
+		return "Restart"
+	default:
+		return fmt.Sprintf("SignalAction(%d)", a)
+	}
+}
+
+// reloadMu makes ActionReload run the Reloaders one reload at a time.
+var reloadMu sync.Mutex
+
+// runAction performs a non-shutdown SignalAction against the global registry.
+func runAction(ctx context.Context, c *triggerConfig, action SignalAction) {
+	switch action {
+	case ActionReload:
+		go func() {
+			// a signal received during a reload waits for it, then reloads again
+			reloadMu.Lock()
+			defer reloadMu.Unlock()
+
+			reloadCtx := ctx
+			if c.timeout > 0 {
+				rctx, cancel := context.WithTimeout(ctx, c.timeout)
+				reloadCtx = rctx
+				defer cancel()
+			}
+
+			if muErr := defaultRegistry.Reload(reloadCtx); !muErr.IsEmpty() {
+				reloadErrors.MutateValue(func(v *errx.MultiError) {
+					v.Append(muErr)
+				})
+			}
+			log.Printf("gogracefully: Reload completed. Use gogracefully.GlobalReloadError for checks errors\n")
+		}()
+	case ActionDump:
+		dumpState(c.dumpw, defaultRegistry)
+	case ActionForceExit:
+		log.Printf("gogracefully: Forcing exit\n")
+		os.Exit(1)
+	case ActionRestart:
+		if !restarting.CompareAndSwap(false, true) {
+			log.Printf("gogracefully: Restart already in progress\n")
+			return
+		}
+		go func() {
+			defer restarting.Store(false)
+
+			pid, err := restart(ctx, c)
+			if err != nil {
+				log.Printf("gogracefully: Restart failed - %v\n", err)
+				return
+			}
+			triggerShutdown(fmt.Errorf("%w: new process pid %d", ErrRestarted, pid))
+		}()
+	}
+}
+
+// dumpState writes the current status, the registry contents and the stacks
+// of all goroutines to w.
+func dumpState(w io.Writer, r *Registry) {
+	fmt.Fprintf(w, "gogracefully: status: %s\n", GetStatus())
+
+	hooks := r.HookNames()
+	fmt.Fprintf(w, "gogracefully: shutdown hooks (%d):\n", len(hooks))
+	for i, name := range hooks {
+		fmt.Fprintf(w, "\t%d. %s\n", i+1, name)
+	}
+
+	reloaders := r.ReloaderNames()
+	fmt.Fprintf(w, "gogracefully: reloaders (%d):\n", len(reloaders))
+	for i, name := range reloaders {
+		fmt.Fprintf(w, "\t%d. %s\n", i+1, name)
+	}
+
+	fmt.Fprintf(w, "gogracefully: goroutines:\n")
+	_ = pprof.Lookup("goroutine").WriteTo(w, 2)
+}
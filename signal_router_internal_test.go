@@ -0,0 +1,116 @@
+package gracefully
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubReloader struct{}
+
+func (s *stubReloader) Reload(context.Context) error { return nil }
+
+// slowReloader records how many reloads ran at the same time.
+type slowReloader struct {
+	active, maxActive, calls atomic.Int32
+}
+
+func (s *slowReloader) Reload(context.Context) error {
+	n := s.active.Add(1)
+	defer s.active.Add(-1)
+	if n > s.maxActive.Load() {
+		s.maxActive.Store(n)
+	}
+	time.Sleep(20 * time.Millisecond)
+	s.calls.Add(1)
+	return nil
+}
+
+func Test_SignalAction_String(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/known", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "Shutdown", ActionShutdown.String())
+		assert.Equal(t, "Reload", ActionReload.String())
+		assert.Equal(t, "Dump", ActionDump.String())
+		assert.Equal(t, "ForceExit", ActionForceExit.String())
+		assert.Equal(t, "Restart", ActionRestart.String())
+	})
+
+	t.Run("edge/unknown_value", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "SignalAction(42)", SignalAction(42).String())
+	})
+}
+
+func Test_dumpState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/writes_registry_and_goroutines", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := NewRegistry()
+		assert.NoError(t, r.RegisterFunc(func(context.Context) error { return nil }))
+		assert.NoError(t, r.RegisterReloader(&stubReloader{}))
+		buf := &bytes.Buffer{}
+
+		// act
+		dumpState(buf, r)
+
+		// assert
+		out := buf.String()
+		assert.Contains(t, out, "shutdown hooks (1):")
+		assert.Contains(t, out, "Test_dumpState")
+		assert.Contains(t, out, "reloaders (1):")
+		assert.Contains(t, out, "*gracefully.stubReloader")
+		assert.Contains(t, out, "goroutine")
+	})
+}
+
+func Test_SetShutdownTrigger_routedSysSignal(t *testing.T) {
+	// trigger state is global; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	SetGlobal(NewRegistry())
+	sysCh := make(chan os.Signal, 1)
+	SetShutdownTrigger(t.Context(),
+		WithCustomSystemSignal(sysCh),
+		WithSignalAction(syscall.SIGTERM, ActionDump),
+		WithDumpWriter(&bytes.Buffer{}),
+	)
+
+	// act: delivered on the shutdown channel too, as signal.Notify does
+	sysCh <- syscall.SIGTERM
+	time.Sleep(50 * time.Millisecond)
+
+	// assert
+	assert.Equal(t, StatusRunning, GetStatus())
+}
+
+func Test_runAction_reloadOneAtATime(t *testing.T) {
+	// swaps the global registry; avoid parallel here
+	resetTriggerState(t)
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	rl := &slowReloader{}
+	assert.NoError(t, r.RegisterReloader(rl))
+	c := newDefaultTriggerConfig()
+
+	// act
+	runAction(t.Context(), c, ActionReload)
+	runAction(t.Context(), c, ActionReload)
+
+	// assert
+	assert.Eventually(t, func() bool { return rl.calls.Load() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), rl.maxActive.Load())
+}
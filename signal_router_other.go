@@ -0,0 +1,17 @@
+//go:build !unix
+
+package gracefully
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultSignalRoutes returns the routes installed by WithSignalRouter.
+// SIGUSR1 is not available on this platform, so ActionDump is not routed.
+func defaultSignalRoutes() map[os.Signal]SignalAction {
+	return map[os.Signal]SignalAction{
+		syscall.SIGHUP:  ActionReload,
+		syscall.SIGQUIT: ActionForceExit,
+	}
+}
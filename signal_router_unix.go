@@ -0,0 +1,17 @@
+//go:build unix
+
+package gracefully
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultSignalRoutes returns the routes installed by WithSignalRouter.
+func defaultSignalRoutes() map[os.Signal]SignalAction {
+	return map[os.Signal]SignalAction{
+		syscall.SIGHUP:  ActionReload,
+		syscall.SIGUSR1: ActionDump,
+		syscall.SIGQUIT: ActionForceExit,
+	}
+}
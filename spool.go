@@ -0,0 +1,185 @@
+package gracefully
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// Spool is an append-only file for records a hook could not deliver before the
+// shutdown deadline (e.g. the remote sink is down). Records are spilled during
+// shutdown and replayed on the next start.
+//
+// Each record is stored as its length, a CRC-32 checksum and the data.
+//
+// Use NewSpool to create a new instance.
+type Spool struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File // opened by the first Spill
+}
+
+// NewSpool returns a Spool backed by the file at path. The file is created by the first Spill.
+//
+// Example:
+//
+//	func (b *batcher) GracefulShutdown(ctx context.Context) error {
+//		if err := b.flush(ctx); err != nil {
+//			return b.spool.Spill(ctx, b.pending...)
+//		}
+//		return nil
+//	}
+func NewSpool(path string) *Spool {
+	return &Spool{path: path}
+}
+
+// Spill appends records to the spool file and syncs it. When called from a hook
+// with the context passed to it, the records are counted in HookReport.Spilled.
+func (s *Spool) Spill(ctx context.Context, records ...[]byte) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	buf := encodeSpool(records)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("spool: %w", err)
+		}
+		s.f = f
+	}
+	if _, err := s.f.Write(buf); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+
+	if rec := hookRecordFrom(ctx); rec != nil {
+		rec.spilled.Add(int64(len(records)))
+	}
+	return nil
+}
+
+// Replay calls deliver for each spooled record, oldest first, and returns how many
+// were delivered. Delivered records are removed from the spool: if deliver fails
+// (or ctx is done), the remaining records are kept for the next Replay.
+//
+// A damaged or incomplete record at the end of the file is dropped and reported
+// with ErrSpoolCorrupted after the preceding records are delivered.
+func (s *Spool) Replay(ctx context.Context, deliver func(record []byte) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.closeFile(); err != nil {
+		return 0, fmt.Errorf("spool: %w", err)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("spool: %w", err)
+	}
+
+	records, corruptErr := decodeSpool(data)
+	for i, rec := range records {
+		err := ctx.Err()
+		if err == nil {
+			err = deliver(rec)
+		}
+		if err != nil {
+			return i, errors.Join(err, s.rewrite(records[i:]))
+		}
+	}
+
+	if err := os.Remove(s.path); err != nil {
+		return len(records), fmt.Errorf("spool: %w", err)
+	}
+	return len(records), corruptErr
+}
+
+// Close closes the spool file. Spill reopens it.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closeFile()
+}
+
+func (s *Spool) closeFile() error {
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// rewrite atomically replaces the spool file with records.
+func (s *Spool) rewrite(records [][]byte) error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	defer os.Remove(tmp) // no-op after a successful rename
+
+	_, err = f.Write(encodeSpool(records))
+	if err == nil {
+		err = f.Sync()
+	}
+	if err = errors.Join(err, f.Close()); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("spool: %w", err)
+	}
+	return nil
+}
+
+func encodeSpool(records [][]byte) []byte {
+	var buf []byte
+	for _, rec := range records {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(rec)))
+		buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(rec))
+		buf = append(buf, rec...)
+	}
+	return buf
+}
+
+// decodeSpool returns the records in data and an error wrapping ErrSpoolCorrupted
+// if data ends with a damaged record.
+func decodeSpool(data []byte) ([][]byte, error) {
+	var records [][]byte
+	for off := 0; off < len(data); {
+		if len(data)-off < 8 {
+			return records, fmt.Errorf("%w: truncated header at offset %d", ErrSpoolCorrupted, off)
+		}
+		n := int(binary.BigEndian.Uint32(data[off:]))
+		sum := binary.BigEndian.Uint32(data[off+4:])
+		off += 8
+
+		if len(data)-off < n {
+			return records, fmt.Errorf("%w: truncated record at offset %d", ErrSpoolCorrupted, off-8)
+		}
+		rec := data[off : off+n]
+		if crc32.ChecksumIEEE(rec) != sum {
+			return records, fmt.Errorf("%w: checksum mismatch at offset %d", ErrSpoolCorrupted, off-8)
+		}
+		records = append(records, rec)
+		off += n
+	}
+	return records, nil
+}
@@ -0,0 +1,127 @@
+package gracefully_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+// replayAll replays s and returns the delivered records.
+func replayAll(t *testing.T, s *gracefully.Spool) ([]string, error) {
+	var got []string
+	_, err := s.Replay(context.Background(), func(rec []byte) error {
+		got = append(got, string(rec))
+		return nil
+	})
+	return got, err
+}
+
+func Test_Spool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/spillsAndReplays", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		path := filepath.Join(t.TempDir(), "events.spool")
+		s := gracefully.NewSpool(path)
+		assert.NoError(t, s.Spill(context.Background(), []byte("a"), []byte("b")))
+		assert.NoError(t, s.Spill(context.Background(), []byte("")))
+		assert.NoError(t, s.Close())
+
+		// act
+		got, err := replayAll(t, gracefully.NewSpool(path))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", ""}, got)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("ok/nothingSpooled", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		s := gracefully.NewSpool(filepath.Join(t.TempDir(), "events.spool"))
+
+		// act
+		n, err := s.Replay(context.Background(), func([]byte) error { return nil })
+
+		// assert
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("err/keepsUndelivered", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		boom := errors.New("sink down")
+		s := gracefully.NewSpool(filepath.Join(t.TempDir(), "events.spool"))
+		assert.NoError(t, s.Spill(context.Background(), []byte("a"), []byte("b"), []byte("c")))
+
+		// act
+		n, err := s.Replay(context.Background(), func(rec []byte) error {
+			if string(rec) == "b" {
+				return boom
+			}
+			return nil
+		})
+
+		// assert
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, 1, n)
+		got, err := replayAll(t, s)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b", "c"}, got)
+	})
+
+	t.Run("err/corruptedTail", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		path := filepath.Join(t.TempDir(), "events.spool")
+		s := gracefully.NewSpool(path)
+		assert.NoError(t, s.Spill(context.Background(), []byte("a"), []byte("bb")))
+		assert.NoError(t, s.Close())
+		raw, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, raw[:len(raw)-1], 0o644)) // crashed mid-write
+
+		// act
+		got, err := replayAll(t, s)
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrSpoolCorrupted)
+		assert.Equal(t, []string{"a"}, got)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("ok/countedInReport", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		s := gracefully.NewSpool(filepath.Join(t.TempDir(), "events.spool"))
+		r := gracefully.NewRegistry()
+		child, err := r.NewChild("consumers")
+		assert.NoError(t, err)
+		assert.NoError(t, child.RegisterFunc(func(ctx context.Context) error {
+			return s.Spill(ctx, []byte("a"), []byte("b"))
+		}))
+		assert.NoError(t, r.RegisterFunc(func(ctx context.Context) error {
+			return s.Spill(ctx, []byte("c"))
+		}))
+
+		// act
+		me := r.Shutdown(context.Background())
+
+		// assert
+		assert.True(t, me.IsEmpty())
+		report, ok := r.Report()
+		assert.True(t, ok)
+		assert.Equal(t, 3, report.Spilled())
+		assert.Equal(t, "consumers", report.Hooks[0].Name)
+		assert.Equal(t, 2, report.Hooks[0].Spilled)
+		assert.Equal(t, 1, report.Hooks[1].Spilled)
+	})
+}
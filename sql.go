@@ -0,0 +1,60 @@
+package gracefully
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// sqlPollInterval is how often the SQLDB adapter checks the connections in use.
+const sqlPollInterval = 50 * time.Millisecond
+
+// sqlDB adapts *sql.DB to GracefulShutdownObject.
+type sqlDB struct {
+	db *sql.DB
+}
+
+// SQLDB returns a GracefulShutdownObject that drains db: idle connections are
+// closed and connections are no longer kept after use, then it waits until no
+// connection is in use (see sql.DBStats.InUse) and closes db. Once ctx is done
+// db is closed anyway and the number of connections still in use is reported
+// with ErrConnectionsInUse.
+//
+// Register it after the hooks that stop issuing queries (e.g. the HTTP server).
+// Registrations are identified by db, so Unregister(SQLDB(db)) works.
+//
+// Example:
+//
+//	db, err := sql.Open("postgres", dsn)
+//	...
+//	gracefully.MustRegister(gracefully.HTTPServer(srv), gracefully.SQLDB(db))
+func SQLDB(db *sql.DB) GracefulShutdownObject {
+	return &sqlDB{db: db}
+}
+
+// wrapped implements wrapper.
+func (s *sqlDB) wrapped() any { return s.db }
+
+// GracefulShutdown implements GracefulShutdownObject.
+func (s *sqlDB) GracefulShutdown(ctx context.Context) error {
+	s.db.SetMaxIdleConns(0) // released connections are closed instead of reused
+
+	t := time.NewTicker(sqlPollInterval)
+	defer t.Stop()
+
+	for s.db.Stats().InUse > 0 {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			inUse := s.db.Stats().InUse
+			if inUse == 0 {
+				return s.db.Close()
+			}
+			err := fmt.Errorf("%w: %d connection(s): %w", ErrConnectionsInUse, inUse, ctx.Err())
+			return errors.Join(err, s.db.Close())
+		}
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,93 @@
+package gracefully_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lif0/go-gracefully"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver is a database/sql driver whose connections do nothing.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func init() { sql.Register("gracefully-fake", fakeDriver{}) }
+
+func openFakeDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("gracefully-fake", "")
+	assert.NoError(t, err)
+	return db
+}
+
+func Test_SQLDB(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/waitsForConnections", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		db := openFakeDB(t)
+		conn, err := db.Conn(context.Background())
+		assert.NoError(t, err)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			conn.Close()
+		}()
+
+		// act
+		err = gracefully.SQLDB(db).GracefulShutdown(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Zero(t, db.Stats().InUse)
+		assert.Error(t, db.Ping(), "pool is closed")
+	})
+
+	t.Run("err/reportsInUseAtDeadline", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		db := openFakeDB(t)
+		for range 2 {
+			conn, err := db.Conn(context.Background())
+			assert.NoError(t, err)
+			defer conn.Close()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := gracefully.SQLDB(db).GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, gracefully.ErrConnectionsInUse)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, ": 2 connection(s)")
+		assert.Error(t, db.Ping(), "pool is closed")
+	})
+
+	t.Run("ok/unregisterByDB", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		r := gracefully.NewRegistry()
+		db := openFakeDB(t)
+		r.MustRegister(gracefully.SQLDB(db))
+
+		// act
+		ok := r.Unregister(gracefully.SQLDB(db))
+
+		// assert
+		assert.True(t, ok)
+		assert.Empty(t, r.HookNames())
+	})
+}
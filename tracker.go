@@ -0,0 +1,98 @@
+package gracefully
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Tracker counts units of active work (requests, messages, jobs) and refuses
+// new ones once shutdown starts. It is a WaitGroup that understands Status.
+//
+// Tracker implements GracefulShutdownObject: register it (before the hooks the
+// work depends on) to wait for the active work during shutdown.
+//
+// Use NewTracker to create a new instance.
+type Tracker struct {
+	mu     sync.Mutex
+	active int
+	idle   chan struct{} // closed while active == 0
+	closed bool
+
+	status func() Status
+}
+
+// NewTracker creates and returns a new Tracker.
+func NewTracker() *Tracker {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &Tracker{
+		idle:   idle,
+		status: GetStatus,
+	}
+}
+
+// Acquire registers a unit of work. It returns ok == false, and does not count the
+// unit, once the status is no longer StatusRunning or GracefulShutdown was called.
+// Otherwise, release must be called when the work is done; calling it more than once is a no-op.
+//
+// Example:
+//
+//	ok, release := tracker.Acquire()
+//	if !ok {
+//		return ErrShuttingDown
+//	}
+//	defer release()
+func (t *Tracker) Acquire() (ok bool, release func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed || t.status() != StatusRunning {
+		return false, func() {}
+	}
+
+	if t.active == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.active++
+
+	var once sync.Once
+	return true, func() { once.Do(t.release) }
+}
+
+// Active returns the number of units of work currently in progress.
+func (t *Tracker) Active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.active
+}
+
+// GracefulShutdown implements GracefulShutdownObject.
+// It refuses new work and waits for the active work to finish. If ctx is done
+// first, it returns an error wrapping ErrWorkAbandoned and ctx.Err() that reports
+// how many units were abandoned.
+func (t *Tracker) GracefulShutdown(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %d unit(s): %w", ErrWorkAbandoned, t.Active(), ctx.Err())
+	}
+}
+
+func (t *Tracker) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.active--
+	if t.active == 0 {
+		close(t.idle)
+	}
+}
@@ -0,0 +1,133 @@
+package gracefully
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTracker returns a Tracker that doesn't depend on the global status.
+func newTestTracker(s Status) *Tracker {
+	t := NewTracker()
+	t.status = func() Status { return s }
+	return t
+}
+
+func Test_Tracker_Acquire(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/countsActive", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		tr := newTestTracker(StatusRunning)
+
+		// act
+		ok1, release1 := tr.Acquire()
+		ok2, release2 := tr.Acquire()
+
+		// assert
+		assert.True(t, ok1)
+		assert.True(t, ok2)
+		assert.Equal(t, 2, tr.Active())
+		release1()
+		release1() // idempotent
+		assert.Equal(t, 1, tr.Active())
+		release2()
+		assert.Equal(t, 0, tr.Active())
+	})
+
+	t.Run("ok/refusedWhileDraining", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		tr := newTestTracker(StatusDraining)
+
+		// act
+		ok, release := tr.Acquire()
+		release()
+
+		// assert
+		assert.False(t, ok)
+		assert.Equal(t, 0, tr.Active())
+	})
+
+	t.Run("ok/refusedAfterShutdown", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		tr := newTestTracker(StatusRunning)
+		assert.NoError(t, tr.GracefulShutdown(context.Background()))
+
+		// act
+		ok, _ := tr.Acquire()
+
+		// assert
+		assert.False(t, ok)
+	})
+
+	t.Run("race/concurrent", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		tr := newTestTracker(StatusRunning)
+		const n = 64
+		var wg sync.WaitGroup
+		wg.Add(n)
+
+		// act
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+				if ok, release := tr.Acquire(); ok {
+					defer release()
+				}
+			}()
+		}
+		wg.Wait()
+
+		// assert
+		assert.Equal(t, 0, tr.Active())
+	})
+}
+
+func Test_Tracker_GracefulShutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/waitsForActive", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		tr := newTestTracker(StatusRunning)
+		_, release := tr.Acquire()
+		done := make(chan error)
+
+		// act
+		go func() { done <- tr.GracefulShutdown(context.Background()) }()
+
+		// assert
+		select {
+		case <-done:
+			t.Fatalf("shutdown must wait for active work")
+		case <-time.After(50 * time.Millisecond):
+		}
+		release()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("err/reportsAbandoned", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		tr := newTestTracker(StatusRunning)
+		_, _ = tr.Acquire()
+		_, _ = tr.Acquire()
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := tr.GracefulShutdown(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, ErrWorkAbandoned)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "2 unit(s)")
+	})
+}
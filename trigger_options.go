@@ -1,6 +1,10 @@
 package gracefully
 
 import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,11 +16,26 @@ type triggerConfig struct {
 	sysch <-chan os.Signal
 	usrch []<-chan struct{}
 
-	timeout time.Duration
+	watchers []watcher
+
+	routes map[os.Signal]SignalAction
+	dumpw  io.Writer
+
+	restartListeners []net.Listener
+	restartTimeout   time.Duration
+
+	timeout      time.Duration
+	preStopDelay time.Duration
 }
 
 type TriggerOption func(*triggerConfig)
 
+// watcher is a background shutdown source started by SetShutdownTrigger.
+// It calls fire once its condition is met and should return when ctx is done.
+// After AbortShutdown, the watcher is called again with a new ctx.
+// The cause passed to fire is reported by ShutdownCause.
+type watcher func(ctx context.Context, fire func(cause error))
+
 // WithCustomSystemSignal adds a custom OS signal channel
 //
 // Example:
@@ -55,6 +74,174 @@ func WithUserChanSignal(uch ...<-chan struct{}) TriggerOption {
 	}
 }
 
+// WithSignalAction routes the OS signal sig to action. A routed signal only runs its
+// action: routing SIGINT or SIGTERM to an action other than ActionShutdown means it
+// no longer starts graceful shutdown. Routing a signal to ActionShutdown behaves like
+// WithSysSignal for that signal.
+//
+// Example:
+//
+//	gogracefully.SetShutdownTrigger(ctx, WithSignalAction(syscall.SIGHUP, ActionReload))
+func WithSignalAction(sig os.Signal, action SignalAction) TriggerOption {
+	return func(c *triggerConfig) {
+		if c.routes == nil {
+			c.routes = make(map[os.Signal]SignalAction)
+		}
+		c.routes[sig] = action
+	}
+}
+
+// WithSignalRouter installs the default signal routes:
+//
+// SIGHUP  - runs the registered Reloaders (ActionReload).
+// SIGUSR1 - dumps goroutine stacks and the registry contents (ActionDump).
+// SIGQUIT - forces immediate exit without running hooks (ActionForceExit).
+//
+// Routes can be overridden by WithSignalAction passed after WithSignalRouter.
+func WithSignalRouter() TriggerOption {
+	return func(c *triggerConfig) {
+		for sig, action := range defaultSignalRoutes() {
+			WithSignalAction(sig, action)(c)
+		}
+	}
+}
+
+// WithDumpWriter sets the destination of ActionDump output.
+// By default, the dump is written to os.Stderr.
+func WithDumpWriter(w io.Writer) TriggerOption {
+	return func(c *triggerConfig) {
+		c.dumpw = w
+	}
+}
+
+// WithParentDeathSignal starts graceful shutdown when the parent process exits,
+// e.g. when the supervisor or test harness that spawned the process dies.
+// The shutdown cause is ErrParentDied.
+//
+// The parent is detected by polling os.Getppid(): once the parent exits,
+// the process is re-parented and the parent pid changes. The parent pid is
+// recorded when the option is applied, so a parent that exits before the trigger
+// is set, or while a shutdown is aborted, is still reported.
+//
+// ⚠️ The copy started by WithRestart has the old process as its parent, so this
+// trigger shuts the copy down as soon as the old process exits. Don't combine them.
+//
+// Example:
+//
+//	gogracefully.SetShutdownTrigger(ctx, WithParentDeathSignal())
+func WithParentDeathSignal() TriggerOption {
+	return func(c *triggerConfig) {
+		c.watchers = append(c.watchers, watchParent(os.Getppid, os.Getppid(), parentPollInterval))
+	}
+}
+
+// WithReaderEOF starts graceful shutdown when r reaches EOF, e.g. when the pipe
+// connected to the process is closed by the process that spawned it.
+// A read error other than io.EOF also starts the shutdown. The shutdown cause is ErrInputClosed.
+//
+// ⚠️ Everything read from r is discarded, so r must not be read by anyone else.
+func WithReaderEOF(r io.Reader) TriggerOption {
+	return func(c *triggerConfig) {
+		c.watchers = append(c.watchers, watchReader(r))
+	}
+}
+
+// WithStdinClosed starts graceful shutdown when stdin is closed.
+// It is a shortcut for WithReaderEOF(os.Stdin).
+//
+// Example:
+//
+//	gogracefully.SetShutdownTrigger(ctx, WithStdinClosed())
+func WithStdinClosed() TriggerOption {
+	return WithReaderEOF(os.Stdin)
+}
+
+// WithFileTrigger starts graceful shutdown when the file at path appears, or is modified
+// if it already exists when the trigger is set. It is meant for deployment tooling
+// that can touch files but cannot send signals. The shutdown cause is ErrFileTriggered.
+//
+// On Linux the parent directory is watched with inotify, pollInterval is the fallback
+// (and the only mechanism on other platforms). A non-positive pollInterval defaults to one second.
+// Once shutdown completes, the file is removed to acknowledge it. If the shutdown
+// is aborted (see AbortShutdown), the file is kept and has to change again to trigger.
+//
+// Example:
+//
+//	gogracefully.SetShutdownTrigger(ctx, WithFileTrigger("/run/myapp/stop", 5*time.Second))
+func WithFileTrigger(path string, pollInterval time.Duration) TriggerOption {
+	return func(c *triggerConfig) {
+		if pollInterval <= 0 {
+			pollInterval = filePollInterval
+		}
+		c.watchers = append(c.watchers, watchFile(path, pollInterval))
+	}
+}
+
+// WithMaxLifetime starts graceful shutdown d plus a random duration in [0, jitter)
+// after SetShutdownTrigger. The jitter spreads restarts of replicas started at the
+// same time. The timer starts again after AbortShutdown and Reset.
+// The shutdown cause is ErrMaxLifetime.
+// A non-positive d disables the trigger.
+//
+// Example:
+//
+//	WithMaxLifetime(6*time.Hour, 30*time.Minute)
+func WithMaxLifetime(d, jitter time.Duration) TriggerOption {
+	return func(c *triggerConfig) {
+		if d <= 0 {
+			return
+		}
+		if jitter > 0 {
+			d += rand.N(jitter)
+		}
+		c.watchers = append(c.watchers, watchLifetime(d))
+	}
+}
+
+// WithIdleTimeout starts graceful shutdown once no activity has been recorded for d.
+// Activity is recorded with Touch and BeginActivity; the idle period starts no
+// earlier than SetShutdownTrigger. The shutdown cause is ErrIdleTimeout.
+// A non-positive d disables the trigger.
+//
+// Example:
+//
+//	gogracefully.SetShutdownTrigger(ctx, WithIdleTimeout(10*time.Minute))
+//
+//	for job := range jobs {
+//		done := gogracefully.BeginActivity()
+//		process(job)
+//		done()
+//	}
+func WithIdleTimeout(d time.Duration) TriggerOption {
+	return func(c *triggerConfig) {
+		if d <= 0 {
+			return
+		}
+		c.watchers = append(c.watchers, watchIdle(d))
+	}
+}
+
+// WithMemoryLimitTrigger starts graceful shutdown once the memory usage reaches
+// threshold bytes, so buffered data can be flushed before the process is OOM-killed.
+// The shutdown cause is a *MemoryLimitError carrying the measured value.
+//
+// The usage of the process's own cgroup (memory.current, or memory.usage_in_bytes
+// on cgroup v1) is sampled when available, otherwise the memory mapped by the
+// Go runtime (runtime/metrics).
+// A zero threshold disables the trigger.
+//
+// Example:
+//
+//	WithMemoryLimitTrigger(900 << 20) // 900 MiB of a 1 GiB container limit
+func WithMemoryLimitTrigger(threshold uint64) TriggerOption {
+	return func(c *triggerConfig) {
+		if threshold == 0 {
+			return
+		}
+		c.watchers = append(c.watchers, watchMemory(threshold, memoryPollInterval, sampleMemory))
+	}
+}
+
 // WithTimeout sets the maximum duration for the graceful shutdown.
 // By default, no timeout is applied - the service waits for all tasks to finish.
 // A non-positive timeout disables the shutdown deadline.
@@ -68,11 +255,60 @@ func WithTimeout(timeout time.Duration) TriggerOption {
 	}
 }
 
+// WithPreStopDelay delays running the shutdown hooks by d after the trigger fires.
+// During the delay the status is already StatusDraining: readiness checks fail so
+// load balancers stop sending traffic, and Middleware and Tracker.Acquire reject new
+// work, while in-flight work continues.
+// The pending shutdown can be canceled with AbortShutdown until the delay ends.
+// If the context passed to SetShutdownTrigger is canceled during the delay,
+// the hooks are not run.
+// By default, there is no delay.
+//
+// Example:
+//
+//	WithPreStopDelay(5 * time.Second)
+func WithPreStopDelay(d time.Duration) TriggerOption {
+	return func(c *triggerConfig) {
+		c.preStopDelay = d
+	}
+}
+
+// WithRestart routes sig (usually SIGUSR2) to ActionRestart: the process starts
+// a new copy of itself that inherits listeners, waits until the copy calls
+// NotifyReady and then starts graceful shutdown with ErrRestarted as the cause.
+// The copy picks the listeners up with InheritedListeners, in the same order.
+//
+// Listeners must be backed by a file descriptor (*net.TCPListener, *net.UnixListener,
+// or a TrackingListener wrapping one). Not supported on windows.
+// The copy is a child of the old process: with WithParentDeathSignal it would shut
+// down as soon as the old process exits.
+//
+// Example:
+//
+//	WithRestart(syscall.SIGUSR2, ln)
+func WithRestart(sig os.Signal, listeners ...net.Listener) TriggerOption {
+	return func(c *triggerConfig) {
+		WithSignalAction(sig, ActionRestart)(c)
+		c.restartListeners = listeners
+	}
+}
+
+// WithRestartTimeout sets how long ActionRestart waits for the new copy of the
+// process to call NotifyReady. If it doesn't, the copy is killed and the process
+// keeps running. A non-positive timeout disables it. Default: 30s.
+func WithRestartTimeout(d time.Duration) TriggerOption {
+	return func(c *triggerConfig) {
+		c.restartTimeout = d
+	}
+}
+
 // newDefaultTriggerConfig create default config
 func newDefaultTriggerConfig() *triggerConfig {
 	config := &triggerConfig{}
 	WithSysSignal()(config)
 	WithTimeout(0)(config)
+	WithDumpWriter(os.Stderr)(config)
+	WithRestartTimeout(30 * time.Second)(config)
 
 	return config
 }
@@ -1,7 +1,10 @@
 package gracefully
 
 import (
+	"bytes"
+	"net"
 	"os"
+	"syscall"
 	"testing"
 	"time"
 
@@ -137,3 +140,201 @@ func Test_newDefaultTriggerConfig(t *testing.T) {
 		assert.NotNil(t, b.sysch)
 	})
 }
+
+func Test_WithSignalAction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_route", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithSignalAction(syscall.SIGHUP, ActionReload)(cfg)
+
+		// assert
+		assert.Equal(t, map[os.Signal]SignalAction{syscall.SIGHUP: ActionReload}, cfg.routes)
+	})
+
+	t.Run("ok/overrides_router_default", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithSignalRouter()(cfg)
+		WithSignalAction(syscall.SIGQUIT, ActionShutdown)(cfg)
+
+		// assert
+		assert.Equal(t, ActionReload, cfg.routes[syscall.SIGHUP])
+		assert.Equal(t, ActionShutdown, cfg.routes[syscall.SIGQUIT])
+	})
+}
+
+func Test_WithDumpWriter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/assigns_writer", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+		buf := &bytes.Buffer{}
+
+		// act
+		WithDumpWriter(buf)(cfg)
+
+		// assert
+		assert.Same(t, buf, cfg.dumpw)
+	})
+}
+
+func Test_WithParentDeathSignal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_watcher", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithParentDeathSignal()(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 1)
+	})
+}
+
+func Test_WithReaderEOF(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_watcher", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithReaderEOF(&bytes.Buffer{})(cfg)
+		WithStdinClosed()(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 2)
+	})
+}
+
+func Test_WithFileTrigger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_watcher", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithFileTrigger("stop", 0)(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 1)
+	})
+}
+
+func Test_WithMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_watcher", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithMaxLifetime(time.Hour, time.Minute)(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 1)
+	})
+
+	t.Run("edge/non_positive_disabled", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithMaxLifetime(0, time.Minute)(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 0)
+	})
+}
+
+func Test_WithIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_watcher", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithIdleTimeout(time.Minute)(cfg)
+		WithIdleTimeout(-1)(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 1)
+	})
+}
+
+func Test_WithMemoryLimitTrigger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/adds_watcher", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithMemoryLimitTrigger(1 << 30)(cfg)
+		WithMemoryLimitTrigger(0)(cfg)
+
+		// assert
+		assert.Len(t, cfg.watchers, 1)
+	})
+}
+
+func Test_WithPreStopDelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/assigns_value", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := &triggerConfig{}
+
+		// act
+		WithPreStopDelay(time.Second)(cfg)
+
+		// assert
+		assert.Equal(t, time.Second, cfg.preStopDelay)
+	})
+}
+
+func Test_WithRestart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/routesSignalAndKeepsListeners", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cfg := newDefaultTriggerConfig()
+		ln := fakeListener{}
+
+		// act
+		WithRestart(os.Interrupt, ln)(cfg)
+
+		// assert
+		assert.Equal(t, ActionRestart, cfg.routes[os.Interrupt])
+		assert.Equal(t, []net.Listener{ln}, cfg.restartListeners)
+		assert.Equal(t, 30*time.Second, cfg.restartTimeout)
+	})
+}
+
+// fakeListener is a net.Listener without a file descriptor.
+type fakeListener struct{ net.Listener }
+
+func (fakeListener) Addr() net.Addr { return &net.TCPAddr{} }
@@ -0,0 +1,182 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// parentPollInterval is how often WithParentDeathSignal checks the parent pid.
+	parentPollInterval = time.Second
+
+	// filePollInterval is the default poll interval of WithFileTrigger.
+	filePollInterval = time.Second
+
+	// memoryPollInterval is how often WithMemoryLimitTrigger samples the memory usage.
+	memoryPollInterval = time.Second
+)
+
+// watchParent returns a watcher that fires ErrParentDied once getppid
+// reports a pid other than ppid, the parent observed when the option was applied.
+// A parent that died before the watcher started (or restarted) is reported
+// on the first check.
+func watchParent(getppid func() int, ppid int, interval time.Duration) watcher {
+	return func(ctx context.Context, fire func(cause error)) {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if getppid() != ppid {
+					fire(ErrParentDied)
+					return
+				}
+			}
+		}
+	}
+}
+
+// watchReader returns a watcher that drains r and fires ErrInputClosed once
+// reading stops. The read itself can't be interrupted, so after ctx is done the
+// goroutine lingers until r is closed, but it never fires.
+// Once fired, it doesn't fire again when restarted: r stays closed.
+func watchReader(r io.Reader) watcher {
+	var closed bool
+	return func(ctx context.Context, fire func(cause error)) {
+		if closed {
+			<-ctx.Done()
+			return
+		}
+
+		_, err := io.Copy(io.Discard, r) // returns nil error on EOF
+		if ctx.Err() != nil {
+			return
+		}
+		closed = true
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			fire(fmt.Errorf("%w: %w", ErrInputClosed, err))
+			return
+		}
+		fire(ErrInputClosed)
+	}
+}
+
+// watchFile returns a watcher that fires ErrFileTriggered once the file at path
+// appears or its modification time or size changes. The file is checked every
+// interval and whenever fileEvents reports activity in its directory.
+// After the registry has shut down, the file is removed. If the shutdown is
+// aborted, the file is kept and the restarted watcher compares against it.
+func watchFile(path string, interval time.Duration) watcher {
+	return func(ctx context.Context, fire func(cause error)) {
+		initial, _ := os.Stat(path) // nil if the file doesn't exist yet
+
+		events, stop := fileEvents(path)
+		defer stop()
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			case <-events:
+			}
+
+			if fileChanged(initial, path) {
+				break
+			}
+		}
+
+		r := defaultRegistry
+		fire(fmt.Errorf("%w: %s", ErrFileTriggered, path))
+
+		select {
+		case <-ctx.Done():
+		case <-r.done():
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// fileChanged reports whether the file at path exists and differs from initial.
+func fileChanged(initial os.FileInfo, path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if initial == nil {
+		return true
+	}
+	return !fi.ModTime().Equal(initial.ModTime()) || fi.Size() != initial.Size()
+}
+
+// watchLifetime returns a watcher that fires ErrMaxLifetime after d.
+func watchLifetime(d time.Duration) watcher {
+	return func(ctx context.Context, fire func(cause error)) {
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+			fire(ErrMaxLifetime)
+		}
+	}
+}
+
+// watchIdle returns a watcher that fires ErrIdleTimeout once idleFor reports at least d.
+func watchIdle(d time.Duration) watcher {
+	return func(ctx context.Context, fire func(cause error)) {
+		start := time.Now()
+
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+
+			idle := idleFor(start)
+			if idle >= d {
+				fire(ErrIdleTimeout)
+				return
+			}
+			t.Reset(d - idle)
+		}
+	}
+}
+
+// watchMemory returns a watcher that fires a *MemoryLimitError once sample
+// reports at least threshold bytes.
+func watchMemory(threshold uint64, interval time.Duration, sample func() (uint64, string)) watcher {
+	return func(ctx context.Context, fire func(cause error)) {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+
+			if used, source := sample(); used >= threshold {
+				fire(&MemoryLimitError{Used: used, Threshold: threshold, Source: source})
+				return
+			}
+		}
+	}
+}
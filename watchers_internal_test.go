@@ -0,0 +1,497 @@
+package gracefully
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collectFire returns a fire func and a channel receiving the fired causes.
+func collectFire() (func(error), <-chan error) {
+	ch := make(chan error, 1)
+	return func(cause error) { ch <- cause }, ch
+}
+
+func Test_watchParent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/fires_when_ppid_changes", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var ppid atomic.Int64
+		ppid.Store(42)
+		fire, fired := collectFire()
+		w := watchParent(func() int { return int(ppid.Load()) }, 42, time.Millisecond)
+
+		// act
+		go w(t.Context(), fire)
+		time.Sleep(10 * time.Millisecond)
+		ppid.Store(1)
+
+		// assert
+		select {
+		case cause := <-fired:
+			assert.ErrorIs(t, cause, ErrParentDied)
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not fire after parent changed")
+		}
+	})
+
+	t.Run("ok/fires_when_parent_died_before_start", func(t *testing.T) {
+		t.Parallel()
+		// arrange: the parent observed by the option is already gone
+		fire, fired := collectFire()
+		w := watchParent(func() int { return 1 }, 42, time.Millisecond)
+
+		// act
+		go w(t.Context(), fire)
+
+		// assert
+		select {
+		case cause := <-fired:
+			assert.ErrorIs(t, cause, ErrParentDied)
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not report the parent that died before it started")
+		}
+	})
+
+	t.Run("ok/stops_on_ctx_done", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		fire, fired := collectFire()
+		w := watchParent(func() int { return 42 }, 42, time.Millisecond)
+		done := make(chan struct{})
+
+		// act
+		go func() {
+			w(ctx, fire)
+			close(done)
+		}()
+		cancel()
+
+		// assert
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not return after ctx was canceled")
+		}
+		assert.Len(t, fired, 0)
+	})
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func Test_watchReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/fires_on_eof", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		pr, pw := io.Pipe()
+		fire, fired := collectFire()
+		w := watchReader(pr)
+
+		// act
+		go w(t.Context(), fire)
+		_, _ = pw.Write([]byte("some input"))
+		assert.Len(t, fired, 0)
+		_ = pw.Close()
+
+		// assert
+		select {
+		case cause := <-fired:
+			assert.Equal(t, ErrInputClosed, cause)
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not fire on EOF")
+		}
+	})
+
+	t.Run("ok/fires_on_read_error", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		readErr := errors.New("broken pipe")
+		fire, fired := collectFire()
+
+		// act
+		watchReader(errReader{err: readErr})(t.Context(), fire)
+
+		// assert
+		cause := <-fired
+		assert.ErrorIs(t, cause, ErrInputClosed)
+		assert.ErrorIs(t, cause, readErr)
+	})
+
+	t.Run("edge/no_fire_after_ctx_done", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fire, fired := collectFire()
+
+		// act
+		watchReader(strings.NewReader(""))(ctx, fire)
+
+		// assert
+		assert.Len(t, fired, 0)
+	})
+
+	t.Run("edge/no_fire_when_restarted_after_eof", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fire, fired := collectFire()
+		w := watchReader(strings.NewReader(""))
+		w(t.Context(), fire)
+		<-fired
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		// act
+		w(ctx, fire)
+
+		// assert
+		assert.Len(t, fired, 0)
+	})
+}
+
+func Test_watchFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/fires_when_file_appears", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		path := filepath.Join(t.TempDir(), "stop")
+		fire, fired := collectFire()
+		w := watchFile(path, 5*time.Millisecond)
+
+		// act
+		go w(t.Context(), fire)
+		time.Sleep(20 * time.Millisecond)
+		assert.Len(t, fired, 0)
+		assert.NoError(t, os.WriteFile(path, nil, 0o644))
+
+		// assert
+		select {
+		case cause := <-fired:
+			assert.ErrorIs(t, cause, ErrFileTriggered)
+			assert.ErrorContains(t, cause, path)
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not fire after file was created")
+		}
+	})
+
+	t.Run("ok/fires_when_existing_file_modified", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		path := filepath.Join(t.TempDir(), "stop")
+		assert.NoError(t, os.WriteFile(path, nil, 0o644))
+		fire, fired := collectFire()
+		w := watchFile(path, 5*time.Millisecond)
+
+		// act
+		go w(t.Context(), fire)
+		time.Sleep(20 * time.Millisecond)
+		assert.Len(t, fired, 0)
+		assert.NoError(t, os.WriteFile(path, []byte("now"), 0o644))
+
+		// assert
+		select {
+		case cause := <-fired:
+			assert.ErrorIs(t, cause, ErrFileTriggered)
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not fire after file was modified")
+		}
+	})
+}
+
+func Test_watchFile_removesAfterShutdown(t *testing.T) {
+	// swaps the global registry; avoid parallel here
+	oldRegistry, oldRegisterer := defaultRegistry, DefaultRegisterer
+	t.Cleanup(func() { defaultRegistry, DefaultRegisterer = oldRegistry, oldRegisterer })
+
+	// arrange
+	r := NewRegistry()
+	SetGlobal(r)
+	path := filepath.Join(t.TempDir(), "stop")
+	assert.NoError(t, os.WriteFile(path, nil, 0o644))
+	fire, fired := collectFire()
+	done := make(chan struct{})
+
+	// act
+	go func() {
+		watchFile(path, time.Millisecond)(t.Context(), fire)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(path, []byte("stop"), 0o644))
+	<-fired
+	assert.FileExists(t, path)
+	_ = r.Shutdown(context.Background())
+
+	// assert
+	select {
+	case <-done:
+		assert.NoFileExists(t, path)
+	case <-time.After(time.Second):
+		t.Fatalf("watcher did not return after shutdown")
+	}
+}
+
+func Test_watchLifetime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/fires_after_lifetime", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fire, fired := collectFire()
+		start := time.Now()
+
+		// act
+		watchLifetime(20*time.Millisecond)(t.Context(), fire)
+
+		// assert
+		assert.ErrorIs(t, <-fired, ErrMaxLifetime)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+}
+
+func Test_watchIdle(t *testing.T) {
+	// activity is global state; avoid parallel here
+	t.Run("ok/fires_when_idle", func(t *testing.T) {
+		// arrange
+		fire, fired := collectFire()
+		start := time.Now()
+
+		// act
+		watchIdle(30*time.Millisecond)(t.Context(), fire)
+
+		// assert
+		assert.ErrorIs(t, <-fired, ErrIdleTimeout)
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	})
+
+	t.Run("ok/touch_postpones", func(t *testing.T) {
+		// arrange
+		fire, fired := collectFire()
+		stop := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-stop:
+					return
+				case <-time.After(5 * time.Millisecond):
+					Touch()
+				}
+			}
+		}()
+		start := time.Now()
+
+		// act
+		go watchIdle(30*time.Millisecond)(t.Context(), fire)
+		time.Sleep(100 * time.Millisecond)
+		assert.Len(t, fired, 0)
+		close(stop)
+
+		// assert
+		assert.ErrorIs(t, <-fired, ErrIdleTimeout)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("ok/in_flight_is_never_idle", func(t *testing.T) {
+		// arrange
+		fire, fired := collectFire()
+		done := BeginActivity()
+
+		// act
+		go watchIdle(10*time.Millisecond)(t.Context(), fire)
+		time.Sleep(50 * time.Millisecond)
+		assert.Len(t, fired, 0)
+		done()
+		done() // idempotent
+
+		// assert
+		assert.ErrorIs(t, <-fired, ErrIdleTimeout)
+		assert.Equal(t, int64(0), inFlight.Load())
+	})
+}
+
+func Test_watchMemory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/fires_with_measured_value", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		var used atomic.Uint64
+		used.Store(100)
+		fire, fired := collectFire()
+		w := watchMemory(1000, time.Millisecond, func() (uint64, string) { return used.Load(), "test" })
+
+		// act
+		go w(t.Context(), fire)
+		time.Sleep(10 * time.Millisecond)
+		assert.Len(t, fired, 0)
+		used.Store(1024)
+
+		// assert
+		select {
+		case cause := <-fired:
+			assert.ErrorIs(t, cause, ErrMemoryLimit)
+			var mle *MemoryLimitError
+			assert.ErrorAs(t, cause, &mle)
+			assert.Equal(t, uint64(1024), mle.Used)
+			assert.Equal(t, uint64(1000), mle.Threshold)
+			assert.Equal(t, "test", mle.Source)
+		case <-time.After(time.Second):
+			t.Fatalf("watcher did not fire after threshold was reached")
+		}
+	})
+}
+
+func Test_sampleMemory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/reports_usage", func(t *testing.T) {
+		t.Parallel()
+		// act
+		used, source := sampleMemory()
+
+		// assert
+		assert.Greater(t, used, uint64(0))
+		assert.Contains(t, []string{memorySourceCgroup, memorySourceRuntime}, source)
+	})
+
+	t.Run("ok/reads_cgroup_file", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		path := filepath.Join(t.TempDir(), "memory.current")
+		assert.NoError(t, os.WriteFile(path, []byte("123456\n"), 0o644))
+
+		// act
+		used, ok := readCgroupMemory(path)
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, uint64(123456), used)
+	})
+
+	t.Run("edge/missing_or_invalid_cgroup_file", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		path := filepath.Join(t.TempDir(), "memory.current")
+		assert.NoError(t, os.WriteFile(path, []byte("max\n"), 0o644))
+
+		// act
+		_, okInvalid := readCgroupMemory(path)
+		_, okMissing := readCgroupMemory(path + ".missing")
+
+		// assert
+		assert.False(t, okInvalid)
+		assert.False(t, okMissing)
+	})
+}
+
+func Test_cgroupMemoryFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok/v2_namespaced", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cgroups := "0::/\n"
+		mountinfo := "30 24 0:26 / /sys/fs/cgroup rw,nosuid - cgroup2 cgroup2 rw\n"
+
+		// act
+		files := cgroupMemoryFiles(cgroups, mountinfo)
+
+		// assert
+		assert.Equal(t, []string{"/sys/fs/cgroup/memory.current"}, files)
+	})
+
+	t.Run("ok/v2_host", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cgroups := "0::/system.slice/app.service\n"
+		mountinfo := "30 24 0:26 / /sys/fs/cgroup rw,nosuid - cgroup2 cgroup2 rw\n"
+
+		// act
+		files := cgroupMemoryFiles(cgroups, mountinfo)
+
+		// assert
+		assert.Equal(t, []string{"/sys/fs/cgroup/system.slice/app.service/memory.current"}, files)
+	})
+
+	t.Run("ok/v1_hybrid", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cgroups := "4:memory:/app/worker\n3:cpu,cpuacct:/app/worker\n0::/\n"
+		mountinfo := "36 32 0:32 / /sys/fs/cgroup/memory rw,relatime - cgroup cgroup rw,memory\n" +
+			"37 32 0:33 / /sys/fs/cgroup/cpu,cpuacct rw,relatime - cgroup cgroup rw,cpu,cpuacct\n" +
+			"42 32 0:38 / /sys/fs/cgroup/unified rw,relatime - cgroup2 cgroup2 rw\n"
+
+		// act
+		files := cgroupMemoryFiles(cgroups, mountinfo)
+
+		// assert
+		assert.Equal(t, []string{
+			"/sys/fs/cgroup/unified/memory.current",
+			"/sys/fs/cgroup/memory/app/worker/memory.usage_in_bytes",
+		}, files)
+	})
+
+	t.Run("ok/v1_container_mount_root", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cgroups := "9:memory:/docker/abc\n"
+		mountinfo := "612 603 0:32 /docker/abc /sys/fs/cgroup/memory ro,nosuid master:15 - cgroup cgroup rw,memory\n"
+
+		// act
+		files := cgroupMemoryFiles(cgroups, mountinfo)
+
+		// assert
+		assert.Equal(t, []string{"/sys/fs/cgroup/memory/memory.usage_in_bytes"}, files)
+	})
+
+	t.Run("edge/v1_root_cgroup_skipped", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cgroups := "4:memory:/\n"
+		mountinfo := "36 32 0:32 / /sys/fs/cgroup/memory rw,relatime - cgroup cgroup rw,memory\n"
+
+		// act
+		files := cgroupMemoryFiles(cgroups, mountinfo)
+
+		// assert
+		assert.Empty(t, files)
+	})
+
+	t.Run("edge/cgroup_outside_mount_root", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cgroups := "9:memory:/other\n"
+		mountinfo := "612 603 0:32 /docker/abc /sys/fs/cgroup/memory ro - cgroup cgroup rw,memory\n"
+
+		// act
+		files := cgroupMemoryFiles(cgroups, mountinfo)
+
+		// assert
+		assert.Empty(t, files)
+	})
+
+	t.Run("edge/no_cgroups", func(t *testing.T) {
+		t.Parallel()
+		// act
+		files := cgroupMemoryFiles("", "")
+
+		// assert
+		assert.Empty(t, files)
+	})
+}
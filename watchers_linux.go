@@ -0,0 +1,45 @@
+//go:build linux
+
+package gracefully
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// fileEvents watches the parent directory of path with inotify and sends to
+// the returned channel whenever something changes there. The returned func
+// releases the watch. If inotify is unavailable, the channel is nil and the
+// caller relies on polling only.
+func fileEvents(path string) (<-chan struct{}, func()) {
+	fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, func() {}
+	}
+
+	const mask = syscall.IN_CREATE | syscall.IN_MOVED_TO | syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_ATTRIB
+	if _, err := syscall.InotifyAddWatch(fd, filepath.Dir(path), mask); err != nil {
+		_ = syscall.Close(fd)
+		return nil, func() {}
+	}
+
+	// a non-blocking fd is served by the runtime poller, so Close unblocks Read.
+	f := os.NewFile(uintptr(fd), "inotify")
+	ch := make(chan struct{}, 1)
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			if _, err := f.Read(buf); err != nil {
+				return
+			}
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return ch, func() { _ = f.Close() }
+}
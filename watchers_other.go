@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gracefully
+
+// fileEvents is not supported on this platform; WithFileTrigger falls back to polling.
+func fileEvents(string) (<-chan struct{}, func()) {
+	return nil, func() {}
+}